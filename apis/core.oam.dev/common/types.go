@@ -437,15 +437,21 @@ const (
 	WorkflowCondition
 	// ReadyCondition indicates whether whole application processing is successful.
 	ReadyCondition
+	// RenderWarningCondition indicates whether the definitions reported warnings during rendering.
+	RenderWarningCondition
+	// ValidationCondition indicates whether the parameters passed the validation of the definitions.
+	ValidationCondition
 )
 
 var conditions = map[ApplicationConditionType]string{
-	ParsedCondition:   "Parsed",
-	RevisionCondition: "Revision",
-	PolicyCondition:   "Policy",
-	RenderCondition:   "Render",
-	WorkflowCondition: "Workflow",
-	ReadyCondition:    "Ready",
+	ParsedCondition:        "Parsed",
+	RevisionCondition:      "Revision",
+	PolicyCondition:        "Policy",
+	RenderCondition:        "Render",
+	WorkflowCondition:      "Workflow",
+	ReadyCondition:         "Ready",
+	RenderWarningCondition: "RenderWarning",
+	ValidationCondition:    "Validation",
 }
 
 // String returns the string corresponding to the condition type.
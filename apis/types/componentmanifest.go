@@ -30,4 +30,6 @@ type ComponentManifest struct {
 	ComponentOutput *unstructured.Unstructured
 	// ComponentOutputsAndTraits contains both resources generated from "outputs" block of ComponentDefinition and resources generated from TraitDefinition
 	ComponentOutputsAndTraits []*unstructured.Unstructured
+	// Warnings contains the non-fatal diagnostics reported by the "errs" block of the definitions
+	Warnings []string
 }
@@ -31,6 +31,7 @@ const (
 	ReasonFailedApply     = "FailedApply"
 	ReasonFailedStateKeep = "FailedStateKeep"
 	ReasonFailedGC        = "FailedGC"
+	ReasonFailedValidate  = "FailedValidate"
 )
 
 // event message for Application
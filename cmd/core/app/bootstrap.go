@@ -18,6 +18,11 @@ package app
 
 import (
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/oam-dev/kubevela/pkg/config"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
+	"github.com/oam-dev/kubevela/pkg/registry"
 )
 
 // bootstrapProviderRegistry registers framework-level providers that need
@@ -35,7 +40,7 @@ import (
 // 4. Prefer constructor injection for new code without cycles
 //
 // See pkg/registry/README.md for feature overview and pkg/registry package docs for guidelines.
-func bootstrapProviderRegistry() {
+func bootstrapProviderRegistry(manager manager.Manager) {
 	klog.V(2).InfoS("Bootstrapping provider registry")
 
 	// ────────────────────────────────────────────────────────────────────
@@ -47,5 +52,10 @@ func bootstrapProviderRegistry() {
 	// Note: Consider refactoring to extract shared interfaces
 	// registry.RegisterAs[ProviderInterface](implementation)
 
+	// ConfigReader - Reads the configs providing the parameter defaults of definitions
+	// Cycle: pkg/cue/definition ↔ pkg/config (the config factory renders config templates)
+	// Note: Consider extracting the config reading from the config factory
+	registry.RegisterAs[definition.ConfigReader](definition.NewCachedConfigReader(config.NewConfigFactory(manager.GetClient())))
+
 	klog.V(2).InfoS("Provider registry bootstrap complete")
 }
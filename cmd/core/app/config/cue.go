@@ -17,14 +17,23 @@ limitations under the License.
 package config
 
 import (
+	"time"
+
 	"github.com/kubevela/pkg/cue/cuex"
 	"github.com/spf13/pflag"
+
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 )
 
 // CUEConfig contains CUE language configuration.
 type CUEConfig struct {
 	EnableExternalPackage      bool
 	EnableExternalPackageWatch bool
+	DefinitionRenderTimeout    time.Duration
+	DefinitionMaxRenders       int
+	DefinitionMaxOutputs       int
+	DefinitionMaxOutputBytes   int
+	DefinitionAllowedPackages  []string
 }
 
 // NewCUEConfig creates a new CUEConfig with defaults.
@@ -32,6 +41,10 @@ func NewCUEConfig() *CUEConfig {
 	return &CUEConfig{
 		EnableExternalPackage:      cuex.EnableExternalPackageForDefaultCompiler,
 		EnableExternalPackageWatch: cuex.EnableExternalPackageWatchForDefaultCompiler,
+		DefinitionRenderTimeout:    definition.RenderTimeout,
+		DefinitionMaxRenders:       definition.MaxConcurrentRenders,
+		DefinitionMaxOutputs:       definition.MaxRenderOutputs,
+		DefinitionMaxOutputBytes:   definition.MaxOutputBytes,
 	}
 }
 
@@ -45,6 +58,26 @@ func (c *CUEConfig) AddFlags(fs *pflag.FlagSet) {
 		"enable-external-package-watch-for-default-compiler",
 		c.EnableExternalPackageWatch,
 		"Enable watching for changes in external CUE packages and automatically reload them when modified. Requires enable-external-package-for-default-compiler to be enabled.")
+	fs.DurationVar(&c.DefinitionRenderTimeout,
+		"definition-render-timeout",
+		c.DefinitionRenderTimeout,
+		"The max duration the rendering of a definition template including its provider calls is waited for. The rendering fails with an error once exceeded, so that a pathological template cannot hang a reconcile worker, while its CUE evaluation keeps running in the background until it finishes. 0 means no timeout.")
+	fs.IntVar(&c.DefinitionMaxRenders,
+		"definition-max-concurrent-renders",
+		c.DefinitionMaxRenders,
+		"The max number of definition templates evaluated at the same time when definition-render-timeout is set, including the evaluations given up on after the timeout. The renderings wait for a free slot within the timeout. 0 means no limit.")
+	fs.IntVar(&c.DefinitionMaxOutputs,
+		"definition-max-outputs",
+		c.DefinitionMaxOutputs,
+		"The max number of outputs rendered by a single definition template, the rendering fails once exceeded to protect the controller from runaway comprehensions. 0 means no limit.")
+	fs.IntVar(&c.DefinitionMaxOutputBytes,
+		"definition-max-output-bytes",
+		c.DefinitionMaxOutputBytes,
+		"The max size in bytes of a single object rendered by a definition template, the rendering fails once exceeded. 0 means no limit.")
+	fs.StringSliceVar(&c.DefinitionAllowedPackages,
+		"definition-allowed-cue-packages",
+		c.DefinitionAllowedPackages,
+		"The import paths of the cuex Package resources which definition templates are allowed to import without watching the external packages. The package is read when a template imports it for the first time, so it can be applied without restarting the controller.")
 }
 
 // SyncToCUEGlobals syncs the parsed configuration values to CUE package global variables.
@@ -58,4 +91,9 @@ func (c *CUEConfig) AddFlags(fs *pflag.FlagSet) {
 func (c *CUEConfig) SyncToCUEGlobals() {
 	cuex.EnableExternalPackageForDefaultCompiler = c.EnableExternalPackage
 	cuex.EnableExternalPackageWatchForDefaultCompiler = c.EnableExternalPackageWatch
+	definition.RenderTimeout = c.DefinitionRenderTimeout
+	definition.MaxConcurrentRenders = c.DefinitionMaxRenders
+	definition.MaxRenderOutputs = c.DefinitionMaxOutputs
+	definition.MaxOutputBytes = c.DefinitionMaxOutputBytes
+	definition.AllowExternalPackages(c.DefinitionAllowedPackages...)
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	commonconfig "github.com/oam-dev/kubevela/pkg/controller/common"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
 )
@@ -141,6 +142,11 @@ func TestCoreOptions_FlagsCompleteSet(t *testing.T) {
 		// CUE flags
 		"--enable-external-package-for-default-compiler=true",
 		"--enable-external-package-watch-for-default-compiler=true",
+		"--definition-render-timeout=10s",
+		"--definition-max-concurrent-renders=32",
+		"--definition-max-outputs=100",
+		"--definition-max-output-bytes=1048576",
+		"--definition-allowed-cue-packages=ext/echo,ext/hash",
 		// Application flags
 		"--application-re-sync-period=5s",
 		// OAM flags
@@ -199,6 +205,11 @@ func TestCoreOptions_FlagsCompleteSet(t *testing.T) {
 	// Verify CUE flags
 	assert.True(t, opt.CUE.EnableExternalPackage)
 	assert.True(t, opt.CUE.EnableExternalPackageWatch)
+	assert.Equal(t, 10*time.Second, opt.CUE.DefinitionRenderTimeout)
+	assert.Equal(t, 32, opt.CUE.DefinitionMaxRenders)
+	assert.Equal(t, 100, opt.CUE.DefinitionMaxOutputs)
+	assert.Equal(t, 1048576, opt.CUE.DefinitionMaxOutputBytes)
+	assert.Equal(t, []string{"ext/echo", "ext/hash"}, opt.CUE.DefinitionAllowedPackages)
 
 	// Verify Application flags
 	assert.Equal(t, 5*time.Second, opt.Application.ReSyncPeriod)
@@ -231,6 +242,8 @@ func TestCuexOptions_SyncToGlobals(t *testing.T) {
 	// Reset globals
 	cuex.EnableExternalPackageForDefaultCompiler = false
 	cuex.EnableExternalPackageWatchForDefaultCompiler = false
+	origRenderTimeout := definition.RenderTimeout
+	defer func() { definition.RenderTimeout = origRenderTimeout }()
 
 	opts := NewCoreOptions()
 	fss := opts.Flags()
@@ -238,6 +251,7 @@ func TestCuexOptions_SyncToGlobals(t *testing.T) {
 	args := []string{
 		"--enable-external-package-for-default-compiler=true",
 		"--enable-external-package-watch-for-default-compiler=true",
+		"--definition-render-timeout=10s",
 	}
 
 	err := fss.FlagSet("cue").Parse(args)
@@ -251,6 +265,7 @@ func TestCuexOptions_SyncToGlobals(t *testing.T) {
 	opts.CUE.SyncToCUEGlobals()
 	assert.True(t, cuex.EnableExternalPackageForDefaultCompiler)
 	assert.True(t, cuex.EnableExternalPackageWatchForDefaultCompiler)
+	assert.Equal(t, 10*time.Second, definition.RenderTimeout)
 }
 
 func TestWorkflowOptions_SyncToGlobals(t *testing.T) {
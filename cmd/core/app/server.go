@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -56,6 +57,8 @@ import (
 	commonconfig "github.com/oam-dev/kubevela/pkg/controller/common"
 	oamv1beta1 "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1beta1/application"
+	coredef "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1beta1/core"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/logging"
 	"github.com/oam-dev/kubevela/pkg/monitor/watcher"
@@ -186,6 +189,13 @@ func run(ctx context.Context, coreOptions *options.CoreOptions) error {
 		return fmt.Errorf("failed to start application monitor: %w", err)
 	}
 
+	// Start parameter schema watcher
+	klog.InfoS("Starting definition parameter schema watcher")
+	if err := startParameterSchemaWatcher(ctx, manager, coreOptions); err != nil {
+		klog.ErrorS(err, "Failed to start parameter schema watcher")
+		return fmt.Errorf("failed to start parameter schema watcher: %w", err)
+	}
+
 	// Start the manager
 	klog.InfoS("Starting controller manager")
 	if err := manager.Start(ctx); err != nil {
@@ -326,6 +336,9 @@ func buildManagerOptions(ctx context.Context, coreOptions *options.CoreOptions)
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: coreOptions.Observability.MetricsAddr,
+			ExtraHandlers: map[string]http.Handler{
+				definition.ParameterSchemaPath: definition.DefaultParameterSchemaCatalog,
+			},
 		},
 		LeaderElection:          coreOptions.Server.EnableLeaderElection,
 		LeaderElectionNamespace: coreOptions.Server.LeaderElectionNamespace,
@@ -403,6 +416,27 @@ func startApplicationMonitor(ctx context.Context, manager ctrl.Manager) error {
 	return nil
 }
 
+// startParameterSchemaWatcher keeps the parameter schemas served by every replica in sync with the definitions
+func startParameterSchemaWatcher(ctx context.Context, manager ctrl.Manager, coreOptions *options.CoreOptions) error {
+	schemaWatcher := &coredef.ParameterSchemaWatcher{
+		Catalog:            definition.DefaultParameterSchemaCatalog,
+		ControllerVersion:  version.VelaVersion,
+		IgnoreDefNoCtrlReq: coreOptions.Controller.IgnoreDefinitionWithoutControllerRequirement,
+	}
+	for _, obj := range []ctrlclient.Object{&v1beta1.ComponentDefinition{}, &v1beta1.TraitDefinition{}} {
+		informer, err := manager.GetCache().GetInformer(ctx, obj)
+		if err != nil {
+			klog.ErrorS(err, "Unable to get informer for definitions")
+			return err
+		}
+		if err := schemaWatcher.Watch(ctx, informer); err != nil {
+			return err
+		}
+	}
+	klog.V(2).InfoS("Parameter schema watcher started successfully")
+	return nil
+}
+
 // performCleanup handles any necessary cleanup operations
 func performCleanup(coreOptions *options.CoreOptions) {
 	klog.V(2).InfoS("Performing cleanup operations")
@@ -453,7 +487,7 @@ func prepareRunInShardingMode(ctx context.Context, manager manager.Manager, core
 func prepareRun(ctx context.Context, manager manager.Manager, coreOptions *options.CoreOptions) error {
 	// Bootstrap provider registry early before other initialization
 	klog.V(2).InfoS("Initializing provider registry")
-	bootstrapProviderRegistry()
+	bootstrapProviderRegistry(manager)
 
 	if coreOptions.Webhook.UseWebhook {
 		klog.InfoS("Webhook enabled, registering OAM webhooks",
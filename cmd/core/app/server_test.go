@@ -37,6 +37,7 @@ import (
 	"github.com/oam-dev/kubevela/cmd/core/app/config"
 	"github.com/oam-dev/kubevela/cmd/core/app/options"
 	commonconfig "github.com/oam-dev/kubevela/pkg/controller/common"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/version"
 )
 
@@ -478,6 +479,7 @@ var _ = Describe("Server Tests", func() {
 
 				// Verify metrics configuration
 				Expect(managerOpts.Metrics.BindAddress).To(Equal(":8080"))
+				Expect(managerOpts.Metrics.ExtraHandlers).To(HaveKey(definition.ParameterSchemaPath))
 
 				// Verify health probe configuration
 				Expect(managerOpts.HealthProbeBindAddress).To(Equal(":8081"))
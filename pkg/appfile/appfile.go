@@ -18,10 +18,14 @@ package appfile
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/oam-dev/kubevela/pkg/cue/definition/health"
 
@@ -35,6 +39,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/util/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	wfTypesv1alpha1 "github.com/kubevela/pkg/apis/oam/v1alpha1"
@@ -50,6 +55,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/component"
 	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
@@ -185,6 +191,16 @@ type Appfile struct {
 	ReferredObjects  []*unstructured.Unstructured
 
 	app *v1beta1.Application
+	// renderedComponents the rendered manifests of the components, indexed by component name
+	renderedComponents map[string]*types.ComponentManifest
+	renderedMu         sync.Mutex
+	// DispatchedComponentLoader loads the manifest of a component dispatched by the previous reconciles, it provides
+	// the context.componentOutputs of the dependencies which are not rendered by this appfile. The loader returns nil
+	// if the component is not dispatched yet.
+	DispatchedComponentLoader func(compName string) (*types.ComponentManifest, error)
+	// RenderContext is the context the definitions of the appfile are rendered with, e.g. the one carrying the
+	// mock configs and packages of a dry-run
+	RenderContext context.Context
 
 	Debug bool
 }
@@ -272,10 +288,11 @@ func prepareArtifactsData(comps []*types.ComponentManifest) map[string]interface
 
 // GenerateComponentManifests converts an appFile to a slice of ComponentManifest
 func (af *Appfile) GenerateComponentManifests() ([]*types.ComponentManifest, error) {
+	order := af.componentRenderOrder()
 	compManifests := make([]*types.ComponentManifest, len(af.ParsedComponents))
 	af.Artifacts = make([]*types.ComponentManifest, len(af.ParsedComponents))
-	for i, comp := range af.ParsedComponents {
-		cm, err := af.GenerateComponentManifest(comp, nil)
+	for _, i := range order {
+		cm, err := af.GenerateComponentManifest(af.ParsedComponents[i], nil)
 		if err != nil {
 			return nil, err
 		}
@@ -289,23 +306,235 @@ func (af *Appfile) GenerateComponentManifests() ([]*types.ComponentManifest, err
 	return compManifests, nil
 }
 
+// AggregateComplete renders all the components and traits like GenerateComponentManifests, but instead of stopping
+// at the first invalid component or trait, the validation errors of all of them are collected and returned in one
+// *definition.AggregateValidationError grouped by component. Other errors are returned immediately.
+// The components depending on an invalid component are skipped.
+func (af *Appfile) AggregateComplete() ([]*types.ComponentManifest, error) {
+	order := af.componentRenderOrder()
+	report := &definition.AggregateValidationError{}
+	invalid := map[string]bool{}
+	compManifests := make([]*types.ComponentManifest, len(af.ParsedComponents))
+	for _, i := range order {
+		comp := af.ParsedComponents[i]
+		if slices.Any(af.getDependsOn(comp.Name), func(dep string) bool { return invalid[dep] }) {
+			invalid[comp.Name] = true
+			continue
+		}
+		cm, err := af.generateComponentManifest(comp, nil, true)
+		if err != nil {
+			if report.Add(comp.Name, err) {
+				invalid[comp.Name] = true
+				continue
+			}
+			return nil, err
+		}
+		if err = af.SetOAMContract(cm); err != nil {
+			return nil, err
+		}
+		compManifests[i] = cm
+	}
+	if report.HasErrors() {
+		return nil, report
+	}
+	af.Artifacts = compManifests
+	return compManifests, nil
+}
+
 // GenerateComponentManifest generate only one ComponentManifest
 func (af *Appfile) GenerateComponentManifest(comp *Component, mutate func(*velaprocess.ContextData)) (*types.ComponentManifest, error) {
+	return af.generateComponentManifest(comp, mutate, false)
+}
+
+func (af *Appfile) generateComponentManifest(comp *Component, mutate func(*velaprocess.ContextData), aggregate bool) (*types.ComponentManifest, error) {
 	if af.Namespace == "" {
 		af.Namespace = corev1.NamespaceDefault
 	}
 	ctxData := GenerateContextDataFromAppFile(af, comp.Name)
+	componentOutputs, err := af.prepareComponentOutputsData(comp)
+	if err != nil {
+		return nil, err
+	}
+	ctxData.ComponentOutputs = componentOutputs
 	if mutate != nil {
 		mutate(&ctxData)
 	}
 	// generate context here to avoid nil pointer panic
 	comp.Ctx = NewBasicContext(ctxData, comp.Params)
+	var cm *types.ComponentManifest
 	switch comp.CapabilityCategory {
 	case types.TerraformCategory:
-		return generateComponentFromTerraformModule(comp, af.Name, af.Namespace)
+		cm, err = generateComponentFromTerraformModule(comp, af.Name, af.Namespace, aggregate)
 	default:
-		return generateComponentFromCUEModule(comp, ctxData)
+		cm, err = generateComponentFromCUEModule(comp, ctxData, aggregate)
+	}
+	if err != nil {
+		return nil, err
 	}
+	af.recordRenderedComponent(cm)
+	return cm, nil
+}
+
+// recordRenderedComponent keeps the rendered manifest for the components depending on it. A rendering including only
+// part of the traits, e.g. the PostDispatch ones, is merged into the manifest rendered before instead of replacing it.
+func (af *Appfile) recordRenderedComponent(cm *types.ComponentManifest) {
+	af.renderedMu.Lock()
+	defer af.renderedMu.Unlock()
+	if af.renderedComponents == nil {
+		af.renderedComponents = map[string]*types.ComponentManifest{}
+	}
+	prev, found := af.renderedComponents[cm.Name]
+	if !found {
+		af.renderedComponents[cm.Name] = cm
+		return
+	}
+	merged := *cm
+	merged.ComponentOutputsAndTraits = nil
+	rendered := map[string]bool{}
+	for _, obj := range cm.ComponentOutputsAndTraits {
+		if obj != nil {
+			rendered[renderedResourceKey(obj)] = true
+			merged.ComponentOutputsAndTraits = append(merged.ComponentOutputsAndTraits, obj)
+		}
+	}
+	for _, obj := range prev.ComponentOutputsAndTraits {
+		if obj != nil && !rendered[renderedResourceKey(obj)] {
+			merged.ComponentOutputsAndTraits = append(merged.ComponentOutputsAndTraits, obj)
+		}
+	}
+	af.renderedComponents[cm.Name] = &merged
+}
+
+// renderedResourceKey identifies an auxiliary resource by the trait generating it and its name in the outputs
+func renderedResourceKey(obj *unstructured.Unstructured) string {
+	return obj.GetLabels()[oam.TraitTypeLabel] + "/" + obj.GetLabels()[oam.TraitResource]
+}
+
+// getRenderedComponent returns the manifest of the component rendered by the appfile, or dispatched by the previous
+// reconciles if it is not rendered by the appfile
+func (af *Appfile) getRenderedComponent(compName string) (*types.ComponentManifest, error) {
+	af.renderedMu.Lock()
+	cm, found := af.renderedComponents[compName]
+	af.renderedMu.Unlock()
+	if found || af.DispatchedComponentLoader == nil {
+		return cm, nil
+	}
+	return af.DispatchedComponentLoader(compName)
+}
+
+// getDependsOn returns the components the component depends on
+func (af *Appfile) getDependsOn(compName string) []string {
+	for _, comp := range af.Components {
+		if comp.Name == compName {
+			return comp.DependsOn
+		}
+	}
+	return nil
+}
+
+// componentRenderOrder returns the indices of the parsed components in rendering order, the components are rendered
+// after the components they depend on and keep their original order otherwise. The components in a dependency cycle
+// are appended in their original order, only the ones referring to context.componentOutputs fail to render then.
+func (af *Appfile) componentRenderOrder() []int {
+	indices := map[string]int{}
+	for i, comp := range af.ParsedComponents {
+		indices[comp.Name] = i
+	}
+	inDegree := make([]int, len(af.ParsedComponents))
+	dependents := make([][]int, len(af.ParsedComponents))
+	for i, comp := range af.ParsedComponents {
+		for _, dep := range af.getDependsOn(comp.Name) {
+			if j, found := indices[dep]; found && j != i {
+				dependents[j] = append(dependents[j], i)
+				inDegree[i]++
+			}
+		}
+	}
+	var ready, order []int
+	for i := range af.ParsedComponents {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, next)
+		for _, i := range dependents[next] {
+			if inDegree[i]--; inDegree[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+	}
+	for i, degree := range inDegree {
+		if degree > 0 {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// refersComponentOutputs checks whether the template of the component or of its traits refer to the
+// context.componentOutputs
+func refersComponentOutputs(comp *Component) bool {
+	if comp.FullTemplate != nil && definition.RefersContextFields(comp.FullTemplate.TemplateStr, velaprocess.ContextComponentOutputs) {
+		return true
+	}
+	return slices.Any(comp.Traits, func(trait *Trait) bool {
+		return definition.RefersContextFields(trait.Template, velaprocess.ContextComponentOutputs)
+	})
+}
+
+// prepareComponentOutputsData collects the rendered results of the components the component depends on, it allows to
+// `context.componentOutputs.<compName>.output` to access the workload of a dependency
+// `context.componentOutputs.<compName>.outputs.<resourceName>` to access an auxiliary resource
+// `context.componentOutputs.<compName>.status` to access the healthy, message and details of a dependency
+// Only the dependencies declared by dependsOn are exposed, so that the rendering doesn't depend on the order the
+// other components are rendered. Nothing is collected if the templates of the component don't refer to it.
+func (af *Appfile) prepareComponentOutputsData(comp *Component) (map[string]interface{}, error) {
+	if !refersComponentOutputs(comp) {
+		return nil, nil
+	}
+	data := map[string]interface{}{}
+	for _, dep := range af.getDependsOn(comp.Name) {
+		cm, err := af.getRenderedComponent(dep)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "load the outputs of component %s", dep)
+		}
+		if cm == nil {
+			return nil, errors.Errorf("component %s refers to context.%s, but the component %s it depends on has not been rendered yet",
+				comp.Name, velaprocess.ContextComponentOutputs, dep)
+		}
+		compData := map[string]interface{}{}
+		if cm.ComponentOutput != nil {
+			compData[velaprocess.OutputFieldName] = cm.ComponentOutput.Object
+		}
+		outputs := map[string]interface{}{}
+		for _, t := range cm.ComponentOutputsAndTraits {
+			if t == nil || t.GetLabels()[oam.TraitResource] == "" {
+				continue
+			}
+			outputs[t.GetLabels()[oam.TraitResource]] = t.Object
+		}
+		if len(outputs) > 0 {
+			compData[velaprocess.OutputsFieldName] = outputs
+		}
+		if af.app != nil {
+			for _, svc := range af.app.Status.Services {
+				if svc.Name == dep {
+					compData["status"] = map[string]interface{}{
+						"healthy": svc.Healthy,
+						"message": svc.Message,
+						"details": svc.Details,
+					}
+					break
+				}
+			}
+		}
+		data[dep] = compData
+	}
+	return data, nil
 }
 
 // SetOAMContract will set OAM labels and annotations for resources as contract
@@ -499,25 +728,41 @@ func NewBasicContext(contextData velaprocess.ContextData, params map[string]inte
 	return pCtx
 }
 
-func generateComponentFromCUEModule(comp *Component, ctxData velaprocess.ContextData) (*types.ComponentManifest, error) {
+func generateComponentFromCUEModule(comp *Component, ctxData velaprocess.ContextData, aggregate bool) (*types.ComponentManifest, error) {
 	pCtx, err := PrepareProcessContext(comp, ctxData)
 	if err != nil {
 		return nil, err
 	}
-	return baseGenerateComponent(pCtx, comp, ctxData.AppName, ctxData.Namespace)
+	return baseGenerateComponent(pCtx, comp, ctxData.AppName, ctxData.Namespace, aggregate)
 }
 
-func generateComponentFromTerraformModule(comp *Component, appName, ns string) (*types.ComponentManifest, error) {
-	return baseGenerateComponent(comp.Ctx, comp, appName, ns)
+func generateComponentFromTerraformModule(comp *Component, appName, ns string, aggregate bool) (*types.ComponentManifest, error) {
+	return baseGenerateComponent(comp.Ctx, comp, appName, ns, aggregate)
 }
 
-func baseGenerateComponent(pCtx process.Context, comp *Component, appName, ns string) (*types.ComponentManifest, error) {
-	var err error
+// baseGenerateComponent renders the traits of the component into the process context, when aggregate is true the
+// validation errors of all the traits are returned together
+func baseGenerateComponent(pCtx process.Context, comp *Component, appName, ns string, aggregate bool) (*types.ComponentManifest, error) {
 	pCtx.PushData(velaprocess.ContextComponentType, comp.Type)
-	for _, tr := range comp.Traits {
-		if err := tr.EvalContext(pCtx); err != nil {
-			return nil, errors.Wrapf(err, "evaluate template trait=%s app=%s", tr.Name, comp.Name)
-		}
+	names, templates := make([]string, len(comp.Traits)), make([]string, len(comp.Traits))
+	for i, tr := range comp.Traits {
+		names[i], templates[i] = tr.Name, tr.Template
+	}
+	order, err := definition.ScheduleTraits(names, templates)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "schedule traits of component=%s", comp.Name)
+	}
+	traits := make([]definition.TraitRender, 0, len(order))
+	for _, i := range order {
+		tr := comp.Traits[i]
+		traits = append(traits, definition.TraitRender{Name: tr.Name, Engine: tr.engine, Template: tr.Template, Params: tr.Params})
+	}
+	completeTraits := definition.CompleteTraits
+	if aggregate {
+		completeTraits = definition.AggregateCompleteTraits
+	}
+	if err := completeTraits(pCtx, traits); err != nil {
+		return nil, errors.WithMessagef(err, "app=%s", comp.Name)
 	}
 	if patcher := comp.Patch; patcher != nil {
 		workload, auxiliaries := pCtx.Output()
@@ -604,9 +849,36 @@ func evalWorkloadWithContext(pCtx process.Context, comp *Component, ns, appName
 		util.AddLabels(tr, labels)
 		compManifest.ComponentOutputsAndTraits[i] = tr
 	}
+	if feature.DefaultMutableFeatureGate.Enabled(features.EnableStableRenderOutputs) {
+		for _, obj := range append([]*unstructured.Unstructured{compManifest.ComponentOutput}, compManifest.ComponentOutputsAndTraits...) {
+			if err := setContentHash(obj); err != nil {
+				return nil, errors.WithMessagef(err, "compute content hash for component=%s app=%s", comp.Name, appName)
+			}
+		}
+	}
+	compManifest.Warnings = definition.GetRenderWarnings(pCtx)
 	return compManifest, nil
 }
 
+// setContentHash annotates the object with the hash of its content, the previous hash is excluded
+func setContentHash(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	delete(annotations, oam.AnnotationContentHash)
+	// empty annotations are dropped so that the hash doesn't depend on their presence
+	obj.SetAnnotations(annotations)
+	// the keys of maps are sorted by json.Marshal so the hash is stable
+	bs, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(bs)
+	util.AddAnnotations(obj, map[string]string{oam.AnnotationContentHash: hex.EncodeToString(sum[:])})
+	return nil
+}
+
 func generateTerraformConfigurationWorkload(comp *Component, ns string) (*unstructured.Unstructured, error) {
 	if comp.FullTemplate == nil || comp.FullTemplate.Terraform == nil || comp.FullTemplate.Terraform.Configuration == "" {
 		return nil, errors.New(errTerraformConfigurationIsNotSet)
@@ -737,6 +1009,7 @@ func GenerateContextDataFromAppFile(appfile *Appfile, wlName string) velaprocess
 		CompName:        wlName,
 		AppRevisionName: appfile.AppRevisionName,
 		Components:      appfile.Components,
+		Ctx:             appfile.RenderContext,
 	}
 	if appfile.AppAnnotations != nil {
 		data.WorkflowName = appfile.AppAnnotations[oam.AnnotationWorkflowName]
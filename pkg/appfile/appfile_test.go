@@ -19,6 +19,7 @@ package appfile
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -37,6 +38,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
 	"github.com/kubevela/workflow/pkg/cue/model"
 
 	wfTypesv1alpha1 "github.com/kubevela/pkg/apis/oam/v1alpha1"
@@ -50,6 +54,7 @@ import (
 	oamtypes "github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
@@ -741,7 +746,7 @@ if context.componentType == "stateless" {
 }`,
 	}
 	wl := &Component{Type: "stateful", Traits: []*Trait{tr}}
-	cm, err := baseGenerateComponent(pContext, wl, appName, ns)
+	cm, err := baseGenerateComponent(pContext, wl, appName, ns, false)
 	assert.NoError(t, err)
 	assert.Equal(t, cm.ComponentOutputsAndTraits[0].Object["kind"], "StatefulSet")
 	assert.Equal(t, cm.ComponentOutputsAndTraits[0].Object["workflowName"], workflowName)
@@ -1704,6 +1709,239 @@ func TestGenerateComponentManifests(t *testing.T) {
 	}
 }
 
+func TestGenerateComponentManifestsWithComponentOutputs(t *testing.T) {
+	template := `
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: key: parameter.key
+}
+outputs: secret: {
+	apiVersion: "v1"
+	kind:       "Secret"
+	stringData: key: parameter.key
+}
+parameter: key: string
+`
+	trait := &Trait{
+		Name:   "sibling",
+		engine: definition.NewTraitAbstractEngine("sibling"),
+		Template: `
+patch: data: {
+	backend: context.componentOutputs.backend.output.data.key
+	secret:  context.componentOutputs.backend.outputs.secret.stringData.key
+	if context.componentOutputs.backend.status != _|_ {
+		healthy: "\(context.componentOutputs.backend.status.healthy)"
+	}
+}
+`,
+	}
+	backend := &Component{
+		Name:         "backend",
+		Type:         "cm",
+		Params:       map[string]interface{}{"key": "b"},
+		engine:       definition.NewWorkloadAbstractEngine("backend"),
+		FullTemplate: &Template{TemplateStr: template},
+	}
+	frontend := &Component{
+		Name:         "frontend",
+		Type:         "cm",
+		Params:       map[string]interface{}{"key": "f"},
+		engine:       definition.NewWorkloadAbstractEngine("frontend"),
+		FullTemplate: &Template{TemplateStr: template},
+		Traits:       []*Trait{trait},
+	}
+	newAppfile := func() *Appfile {
+		return &Appfile{
+			Name:      "app",
+			Namespace: "default",
+			// the frontend is declared first but rendered after the backend it depends on
+			ParsedComponents: []*Component{frontend, backend},
+			Components: []common.ApplicationComponent{
+				{Name: "frontend", Type: "cm", DependsOn: []string{"backend"}},
+				{Name: "backend", Type: "cm"},
+			},
+			app: &v1beta1.Application{Status: common.AppStatus{
+				Services: []common.ApplicationComponentStatus{{Name: "backend", Healthy: true}},
+			}},
+		}
+	}
+	got, err := newAppfile().GenerateComponentManifests()
+	assert.NoError(t, err)
+	data, _, err := unstructured.NestedStringMap(got[0].ComponentOutput.Object, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "f", "backend": "b", "secret": "b", "healthy": "true"}, data)
+
+	// the backend doesn't refer to the component outputs, nothing is injected into its context
+	assert.Nil(t, backend.Ctx.GetData(process.ContextComponentOutputs))
+
+	_, err = newAppfile().GenerateComponentManifest(frontend, nil)
+	assert.ErrorContains(t, err, "component frontend refers to context.componentOutputs, but the component backend it depends on has not been rendered yet")
+
+	// the outputs of the component dispatched in the previous reconciles are loaded
+	dispatched, err := newAppfile().GenerateComponentManifest(backend, nil)
+	assert.NoError(t, err)
+	af := newAppfile()
+	af.DispatchedComponentLoader = func(compName string) (*oamtypes.ComponentManifest, error) {
+		if compName == "backend" {
+			return dispatched, nil
+		}
+		return nil, nil
+	}
+	cm, err := af.GenerateComponentManifest(frontend, nil)
+	assert.NoError(t, err)
+	data, _, err = unstructured.NestedStringMap(cm.ComponentOutput.Object, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", data["backend"])
+
+	// a later partial rendering of the backend keeps the outputs it doesn't render again
+	af = newAppfile()
+	_, err = af.GenerateComponentManifest(backend, nil)
+	assert.NoError(t, err)
+	partial := *backend
+	partial.FullTemplate = &Template{TemplateStr: `
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: key: parameter.key
+}
+parameter: key: string
+`}
+	_, err = af.GenerateComponentManifest(&partial, nil)
+	assert.NoError(t, err)
+	cm, err = af.GenerateComponentManifest(frontend, nil)
+	assert.NoError(t, err)
+	data, _, err = unstructured.NestedStringMap(cm.ComponentOutput.Object, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", data["secret"])
+
+	// the components in a cycle are rendered in their declared order
+	af = newAppfile()
+	af.Components[1].DependsOn = []string{"frontend"}
+	_, err = af.GenerateComponentManifests()
+	assert.ErrorContains(t, err, "the component backend it depends on has not been rendered yet")
+}
+
+func TestGenerateComponentManifestsWithContentHash(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.EnableStableRenderOutputs, true)
+	newAppfile := func(key string) *Appfile {
+		return &Appfile{
+			Name:      "app",
+			Namespace: "default",
+			ParsedComponents: []*Component{{
+				Name:   "comp",
+				Type:   "cm",
+				Params: map[string]interface{}{"key": key},
+				engine: definition.NewWorkloadAbstractEngine("comp"),
+				FullTemplate: &Template{TemplateStr: `
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: key: parameter.key
+}
+outputs: {
+	zyx: {apiVersion: "v1", kind: "Secret", metadata: name: "zyx"}
+	abc: {apiVersion: "v1", kind: "Secret", metadata: name: "abc"}
+}
+`},
+			}},
+			app: &v1beta1.Application{},
+		}
+	}
+	render := func(key string) *oamtypes.ComponentManifest {
+		got, err := newAppfile(key).GenerateComponentManifests()
+		if !assert.NoError(t, err) || !assert.Len(t, got, 1) {
+			t.FailNow()
+		}
+		return got[0]
+	}
+	first, second, changed := render("a"), render("a"), render("b")
+	if !assert.Len(t, first.ComponentOutputsAndTraits, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "abc", first.ComponentOutputsAndTraits[0].GetName())
+	assert.Equal(t, "zyx", first.ComponentOutputsAndTraits[1].GetName())
+
+	hash := first.ComponentOutput.GetAnnotations()[oam.AnnotationContentHash]
+	assert.NotEmpty(t, hash)
+	assert.Equal(t, hash, second.ComponentOutput.GetAnnotations()[oam.AnnotationContentHash])
+	assert.NotEqual(t, hash, changed.ComponentOutput.GetAnnotations()[oam.AnnotationContentHash])
+	for i, obj := range first.ComponentOutputsAndTraits {
+		assert.NotEmpty(t, obj.GetAnnotations()[oam.AnnotationContentHash])
+		assert.Equal(t, obj.GetAnnotations(), second.ComponentOutputsAndTraits[i].GetAnnotations())
+	}
+
+	// the hash is recomputed without the previous one
+	obj := first.ComponentOutput.DeepCopy()
+	assert.NoError(t, setContentHash(obj))
+	recomputed := obj.GetAnnotations()[oam.AnnotationContentHash]
+	assert.NoError(t, setContentHash(obj))
+	assert.Equal(t, recomputed, obj.GetAnnotations()[oam.AnnotationContentHash])
+}
+
+func TestAggregateComplete(t *testing.T) {
+	workload := `
+parameter: replicas: int & >=1
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	spec: replicas: parameter.replicas
+}
+`
+	newTrait := func(name string, value interface{}) *Trait {
+		return &Trait{
+			Name:     name,
+			Params:   map[string]interface{}{"value": value},
+			Template: fmt.Sprintf("parameter: value: string\npatch: metadata: labels: %q: parameter.value", name),
+			engine:   definition.NewTraitAbstractEngine(name),
+		}
+	}
+	newComponent := func(name string, replicas int, traits ...*Trait) *Component {
+		return &Component{
+			Name:         name,
+			Type:         "worker",
+			Params:       map[string]interface{}{"replicas": replicas},
+			Traits:       traits,
+			engine:       definition.NewWorkloadAbstractEngine(name),
+			FullTemplate: &Template{TemplateStr: workload},
+		}
+	}
+	af := &Appfile{
+		Name:      "app",
+		Namespace: "default",
+		ParsedComponents: []*Component{
+			newComponent("valid", 1, newTrait("a", "a")),
+			newComponent("invalid-workload", 0, newTrait("a", "a")),
+			newComponent("invalid-traits", 1, newTrait("a", 1), newTrait("b", "b"), newTrait("c", 2)),
+		},
+		app: &v1beta1.Application{},
+	}
+	_, err := af.GenerateComponentManifests()
+	assert.ErrorContains(t, err, "workload invalid-workload")
+	assert.NotContains(t, err.Error(), "invalid-traits")
+
+	_, err = af.AggregateComplete()
+	var report *definition.AggregateValidationError
+	if !assert.ErrorAs(t, err, &report) || !assert.Len(t, report.Components, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "invalid-workload", report.Components[0].Component)
+	assert.Len(t, report.Components[0].Errors, 1)
+	assert.Equal(t, "invalid-traits", report.Components[1].Component)
+	if assert.Len(t, report.Components[1].Errors, 2) {
+		assert.Equal(t, "a", report.Components[1].Errors[0].EntityName)
+		assert.Equal(t, "c", report.Components[1].Errors[1].EntityName)
+	}
+	assert.Contains(t, err.Error(), "validation failed for 2 component(s):")
+	assert.Contains(t, err.Error(), "component invalid-traits:\n  validation failed for trait a:")
+
+	af.ParsedComponents = af.ParsedComponents[:1]
+	got, err := af.AggregateComplete()
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, got, af.Artifacts)
+}
+
 func TestGeneratePolicyManifests(t *testing.T) {
 	policyEngine := definition.NewWorkloadAbstractEngine("test-policy")
 	policyTemplate := &Template{
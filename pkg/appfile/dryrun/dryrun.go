@@ -55,7 +55,7 @@ type DryRun interface {
 // NewDryRunOption creates a dry-run option
 func NewDryRunOption(c client.Client, cfg *rest.Config, as []*unstructured.Unstructured, serverSideDryRun bool) *Option {
 	parser := appfile.NewDryRunApplicationParser(c, as)
-	return &Option{c, parser, parser.GenerateAppFileFromApp, cfg, as, serverSideDryRun}
+	return &Option{Client: c, Parser: parser, GenerateAppFile: parser.GenerateAppFileFromApp, cfg: cfg, Auxiliaries: as, serverSideDryRun: serverSideDryRun}
 }
 
 // GenerateAppFileFunc generate the app file model from an application
@@ -74,6 +74,23 @@ type Option struct {
 
 	// serverSideDryRun If set to true, means will dry run via the apiserver.
 	serverSideDryRun bool
+	// offline If set to true, the template context of the definitions is built from the rendered resources
+	// instead of the ones in the cluster.
+	offline bool
+}
+
+// WithOfflineRender makes the dry-run render the definitions without reading any resource from the cluster
+func (d *Option) WithOfflineRender() *Option {
+	d.offline = true
+	d.Parser.WithEngineOptions(d.engineOptions()...)
+	return d
+}
+
+func (d *Option) engineOptions() []definition.EngineOption {
+	if d.offline {
+		return []definition.EngineOption{definition.WithOfflineRender()}
+	}
+	return nil
 }
 
 // validateObjectFromFile will read file into Unstructured object
@@ -153,8 +170,9 @@ func (d *Option) ExecuteDryRun(ctx context.Context, application *v1beta1.Applica
 	if appFile.Namespace == "" {
 		appFile.Namespace = corev1.NamespaceDefault
 	}
+	appFile.RenderContext = ctx
 
-	comps, err := appFile.GenerateComponentManifests()
+	comps, err := appFile.AggregateComplete()
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "cannot generate manifests from components and traits")
 	}
@@ -233,7 +251,7 @@ func (d *Option) ExecuteDryRunWithPolicies(ctx context.Context, application *v1b
 		app.Namespace = appNs.(string)
 	}
 	ctx = oamutil.SetNamespaceInCtx(ctx, app.Namespace)
-	parser := appfile.NewDryRunApplicationParser(d.Client, d.Auxiliaries)
+	parser := appfile.NewDryRunApplicationParser(d.Client, d.Auxiliaries).WithEngineOptions(d.engineOptions()...)
 	af, err := parser.GenerateAppFileFromApp(ctx, app)
 	if err != nil {
 		return err
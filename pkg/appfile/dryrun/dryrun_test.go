@@ -31,6 +31,8 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/appfile"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
 )
 
 var _ = Describe("Test DryRun", func() {
@@ -77,6 +79,36 @@ var _ = Describe("Test DryRun", func() {
 	})
 })
 
+var _ = Describe("Test offline DryRun", func() {
+	It("Test template context is built from the rendered resources", func() {
+		appYAML := readDataFromFile("./testdata/dryrun-app.yaml")
+		app := &v1beta1.Application{}
+		Expect(yaml.Unmarshal([]byte(appYAML), &app)).Should(BeNil())
+		app.SetNamespace("default")
+		accessor := oamutil.NewApplicationResourceNamespaceAccessor("default", "")
+
+		getTemplateContext := func(opt *Option) (map[string]interface{}, error) {
+			af, err := opt.GenerateAppFile(context.Background(), app)
+			Expect(err).Should(BeNil())
+			comp := af.ParsedComponents[0]
+			pCtx, err := appfile.PrepareProcessContext(comp, appfile.GenerateContextDataFromAppFile(af, comp.Name))
+			Expect(err).Should(BeNil())
+			return comp.GetTemplateContext(pCtx, k8sClient, accessor)
+		}
+
+		By("The workload is not in the cluster")
+		_, err := getTemplateContext(NewDryRunOption(k8sClient, cfg, dryrunOpt.Auxiliaries, false))
+		Expect(err).ShouldNot(BeNil())
+
+		By("The workload is taken from the rendering in offline mode")
+		templateContext, err := getTemplateContext(NewDryRunOption(k8sClient, cfg, dryrunOpt.Auxiliaries, false).WithOfflineRender())
+		Expect(err).Should(BeNil())
+		output := templateContext["output"].(map[string]interface{})
+		Expect(output["kind"]).Should(Equal("Deployment"))
+		Expect(output["metadata"].(map[string]interface{})["namespace"]).Should(Equal("default"))
+	})
+})
+
 var _ = Describe("Test dry run with policies", func() {
 	It("Test dry run with override policy", func() {
 
@@ -64,6 +64,8 @@ func (fn TemplateLoaderFn) LoadTemplate(ctx context.Context, c client.Client, ca
 type Parser struct {
 	client     client.Client
 	tmplLoader TemplateLoaderFn
+	// engineOptions configures the definition engines of the parsed components, traits and policies
+	engineOptions []definition.EngineOption
 }
 
 // NewApplicationParser create appfile parser
@@ -82,6 +84,12 @@ func NewDryRunApplicationParser(cli client.Client, defs []*unstructured.Unstruct
 	}
 }
 
+// WithEngineOptions sets the options of the definition engines created for the parsed components, traits and policies
+func (p *Parser) WithEngineOptions(opts ...definition.EngineOption) *Parser {
+	p.engineOptions = opts
+	return p
+}
+
 // GenerateAppFile generate appfile for the application to run, if the application is controlled by PublishVersion,
 // the application revision will be used to create the appfile
 func (p *Parser) GenerateAppFile(ctx context.Context, app *v1beta1.Application) (*Appfile, error) {
@@ -485,7 +493,7 @@ func (p *Parser) makeComponent(ctx context.Context, name, typ string, capType ty
 	if err != nil {
 		return nil, errors.WithMessagef(err, "fetch component/policy type of %s", name)
 	}
-	return p.convertTemplate2Component(name, typ, props, templ)
+	return p.convertTemplate2Component(name, typ, capType, props, templ)
 }
 
 func (p *Parser) makeComponentFromRevision(name, typ string, capType types.CapType, props *runtime.RawExtension, appRev *v1beta1.ApplicationRevision) (*Component, error) {
@@ -494,10 +502,10 @@ func (p *Parser) makeComponentFromRevision(name, typ string, capType types.CapTy
 		return nil, errors.WithMessagef(err, "fetch component/policy type of %s from revision", name)
 	}
 
-	return p.convertTemplate2Component(name, typ, props, templ)
+	return p.convertTemplate2Component(name, typ, capType, props, templ)
 }
 
-func (p *Parser) convertTemplate2Component(name, typ string, props *runtime.RawExtension, templ *Template) (*Component, error) {
+func (p *Parser) convertTemplate2Component(name, typ string, capType types.CapType, props *runtime.RawExtension, templ *Template) (*Component, error) {
 	settings, err := util.RawExtension2Map(props)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "fail to parse settings for %s", name)
@@ -506,6 +514,10 @@ func (p *Parser) convertTemplate2Component(name, typ string, props *runtime.RawE
 	if err != nil {
 		cpType = typ
 	}
+	engine := definition.NewWorkloadAbstractEngine(name, p.engineOptions...)
+	if capType == types.TypePolicy {
+		engine = definition.NewPolicyAbstractEngine(name, p.engineOptions...)
+	}
 	return &Component{
 		Traits:             []*Trait{},
 		Name:               name,
@@ -513,7 +525,7 @@ func (p *Parser) convertTemplate2Component(name, typ string, props *runtime.RawE
 		CapabilityCategory: templ.CapabilityCategory,
 		FullTemplate:       templ,
 		Params:             settings,
-		engine:             definition.NewWorkloadAbstractEngine(name),
+		engine:             engine,
 	}, nil
 }
 
@@ -707,7 +719,7 @@ func (p *Parser) convertTemplate2Trait(name string, properties map[string]interf
 		Template:           templ.TemplateStr,
 		CustomStatusFormat: templ.CustomStatus,
 		FullTemplate:       templ,
-		engine:             definition.NewTraitAbstractEngine(traitName),
+		engine:             definition.NewTraitAbstractEngine(traitName, p.engineOptions...),
 	}, nil
 }
 
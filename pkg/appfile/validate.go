@@ -17,13 +17,13 @@ limitations under the License.
 package appfile
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"cuelang.org/go/cue"
 	"github.com/jeremywohl/flatten/v2"
-	"github.com/kubevela/pkg/cue/cuex"
 	"github.com/kubevela/workflow/pkg/cue/model/value"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
@@ -37,6 +37,7 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
 )
 
@@ -106,8 +107,8 @@ func (p *Parser) ValidateCUESchematicAppfile(a *Appfile) error {
 //  3. When the EnableCueValidation gate is on, ensure *all* non‑optional,
 //     non‑defaulted parameters are provided—either in the Component.Params
 //     block or as workflow‑step inputs.
-//  4. Run cue.Value.Validate to enforce user‑supplied values against
-//     template constraints.
+//  4. Validate the user‑supplied values in the compiled value against the
+//     template constraints, the violations are reported as a CueValidationError.
 func (p *Parser) ValidateComponentParams(ctxData velaprocess.ContextData, wl *Component, app *Appfile) error {
 	// ---------------------------------------------------------------------
 	// 1. Build synthetic CUE source
@@ -118,7 +119,12 @@ func (p *Parser) ValidateComponentParams(ctxData velaprocess.ContextData, wl *Co
 		return errors.WithStack(err)
 	}
 
-	paramSnippet, err := cueParamBlock(wl.Params)
+	// the parameters are defaulted as the rendering does, see definition.ApplyParameterDefaults
+	params, err := defaultedParams(ctx.GetCtx(), ctxData.Namespace, wl)
+	if err != nil {
+		return errors.WithMessagef(err, "component %q", wl.Name)
+	}
+	paramSnippet, err := cueParamBlock(params)
 	if err != nil {
 		return errors.WithMessagef(err, "component %q: invalid params", wl.Name)
 	}
@@ -129,7 +135,7 @@ func (p *Parser) ValidateComponentParams(ctxData velaprocess.ContextData, wl *Co
 		baseCtx,
 	}, "\n")
 
-	val, err := cuex.DefaultCompiler.Get().CompileString(ctx.GetCtx(), cueSrc)
+	val, err := definition.GetCompiler(wl.FullTemplate.TemplateStr).CompileString(ctx.GetCtx(), cueSrc)
 	if err != nil {
 		return errors.WithMessagef(err, "component %q: CUE compile error", wl.Name)
 	}
@@ -137,21 +143,32 @@ func (p *Parser) ValidateComponentParams(ctxData velaprocess.ContextData, wl *Co
 	// ---------------------------------------------------------------------
 	// 2. Strict required‑field enforcement (feature‑gated)
 	// ---------------------------------------------------------------------
-	if err := enforceRequiredParams(val, wl.Params, app); err != nil {
+	if err := enforceRequiredParams(val, params, app); err != nil {
 		return errors.WithMessagef(err, "component %q", wl.Name)
 	}
 
 	// ---------------------------------------------------------------------
 	// 3. Validate concrete values
 	// ---------------------------------------------------------------------
-	paramVal := val.LookupPath(value.FieldPath(velaprocess.ParameterFieldName))
-	if err := paramVal.Validate(cue.Concrete(false)); err != nil {
+	if err := definition.ValidateParameterValue(val, "workload", wl.Name, wl.FullTemplate.TemplateStr); err != nil {
 		return errors.WithMessagef(err, "component %q: parameter constraint violation", wl.Name)
 	}
 
 	return nil
 }
 
+// defaultedParams fills the parameters unspecified in the component with the defaults declared by its template
+func defaultedParams(ctx context.Context, namespace string, wl *Component) (map[string]any, error) {
+	params, err := definition.ApplyParameterDefaults(ctx, namespace, wl.FullTemplate.TemplateStr, wl.Params)
+	if err != nil {
+		return nil, err
+	}
+	if defaulted, ok := params.(map[string]any); ok {
+		return defaulted, nil
+	}
+	return wl.Params, nil
+}
+
 // cueParamBlock marshals the Params map into a `parameter:` block suitable
 // for inclusion in a CUE document.
 func cueParamBlock(params map[string]any) (string, error) {
@@ -490,7 +507,7 @@ func (p *Parser) augmentComponentParamsForValidation(wl *Component, workflowPara
 		baseCtx,
 	}, "\n")
 
-	val, err := cuex.DefaultCompiler.Get().CompileString(ctx.GetCtx(), cueSrc)
+	val, err := definition.GetCompiler(wl.FullTemplate.TemplateStr).CompileString(ctx.GetCtx(), cueSrc)
 	if err != nil {
 		return false, wl.Params // Can't compile, proceed normally
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package appfile
 
 import (
+	"context"
 	"testing"
 
 	"cuelang.org/go/cue"
@@ -179,6 +180,7 @@ func TestParser_ValidateComponentParams(t *testing.T) {
 		compName string
 		template string
 		params   map[string]interface{}
+		configs  definition.MockConfigs
 		wantErr  string
 	}{
 		{
@@ -246,7 +248,24 @@ func TestParser_ValidateComponentParams(t *testing.T) {
 			params: map[string]interface{}{
 				"replicas": -1,
 			},
-			wantErr: "parameter constraint violation",
+			wantErr: "validation failed for workload constraint-violation:\n\nParameter errors:\n  parameter.replicas: invalid value -1 (out of bound >0)",
+		},
+		{
+			name:     "constraint referring the application context",
+			compName: "context-constraint",
+			template: `
+			parameter: {
+				prefix: string & =~"^\(context.appName)-"
+			}
+			output: {
+				apiVersion: "apps/v1"
+				kind: "Deployment"
+			}
+			`,
+			params: map[string]interface{}{
+				"prefix": "myapp-web",
+			},
+			wantErr: "",
 		},
 		{
 			name:     "invalid parameter block",
@@ -265,6 +284,40 @@ func TestParser_ValidateComponentParams(t *testing.T) {
 			},
 			wantErr: "parameter constraint violation",
 		},
+		{
+			name:     "parameter defaulted by config",
+			compName: "defaulted",
+			template: `
+			parameterDefaults: config: "defaults"
+			parameter: {
+				replicas: int & >0
+			}
+			output: {
+				apiVersion: "apps/v1"
+				kind: "Deployment"
+			}
+			`,
+			params:  map[string]interface{}{},
+			configs: definition.MockConfigs{"vela-system/defaults": {"replicas": 3}},
+			wantErr: "",
+		},
+		{
+			name:     "parameter defaults out of the application namespace",
+			compName: "other-namespace",
+			template: `
+			parameterDefaults: {config: "defaults", namespace: "team"}
+			parameter: {
+				replicas: int | *1
+			}
+			output: {
+				apiVersion: "apps/v1"
+				kind: "Deployment"
+			}
+			`,
+			params:  map[string]interface{}{},
+			configs: definition.MockConfigs{"team/defaults": {"replicas": 3}},
+			wantErr: "must be in the namespace vela-system or test-ns",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -280,6 +333,9 @@ func TestParser_ValidateComponentParams(t *testing.T) {
 				Namespace: "test-ns",
 			}
 			ctxData := GenerateContextDataFromAppFile(app, wl.Name)
+			if tc.configs != nil {
+				ctxData.Ctx = definition.WithMockConfigs(context.Background(), tc.configs)
+			}
 			parser := &Parser{}
 			err := parser.ValidateComponentParams(ctxData, wl, app)
 			if tc.wantErr == "" {
@@ -18,6 +18,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
@@ -27,8 +28,12 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/registry"
 	nacosmock "github.com/oam-dev/kubevela/test/mock/nacos"
 )
 
@@ -45,6 +50,54 @@ func TestParseConfigTemplate(t *testing.T) {
 	r.Equal(len(template.Schema.Properties), 4)
 }
 
+func TestReadConfigAsParameterDefaults(t *testing.T) {
+	snapshot := registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(snapshot) })
+
+	cli := fake.NewClientBuilder().WithObjects(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: types.DefaultKubeVelaNS},
+			Data:       map[string][]byte{SaveInputPropertiesKey: []byte(`{"registry":"docker.io"}`)},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensitive", Namespace: types.DefaultKubeVelaNS,
+				Annotations: map[string]string{types.AnnotationConfigSensitive: "true"}},
+		},
+	).Build()
+	registry.RegisterAs[definition.ConfigReader](definition.NewCachedConfigReader(NewConfigFactory(cli)))
+
+	render := func(config string) (string, error) {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+		template := fmt.Sprintf(`
+parameterDefaults: config: %q
+parameter: registry: string
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: registry: parameter.registry
+}
+`, config)
+		if err := definition.NewWorkloadAbstractEngine("comp").Complete(ctx, template, nil); err != nil {
+			return "", err
+		}
+		base, _ := ctx.Output()
+		return base.String()
+	}
+
+	r := require.New(t)
+	s, err := render("defaults")
+	r.NoError(err)
+	r.Contains(s, `registry: "docker.io"`)
+
+	_, err = render("sensitive")
+	r.ErrorContains(err, "read parameter defaults from config vela-system/sensitive")
+	r.ErrorContains(err, ErrSensitiveConfig.Error())
+
+	_, err = render("missing")
+	r.ErrorContains(err, "read parameter defaults from config vela-system/missing")
+	r.ErrorContains(err, "not found")
+}
+
 var _ = Describe("test config factory", func() {
 
 	var fac Factory
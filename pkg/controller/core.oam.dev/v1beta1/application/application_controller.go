@@ -214,6 +214,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	tBeginWorkflowExecution := time.Now()
 	workflowState, err := workflowExecutor.ExecuteRunners(authCtx, runners)
 	metrics.AppReconcileStageDurationHistogram.WithLabelValues("execute-workflow").Observe(time.Since(tBeginWorkflowExecution).Seconds())
+	handler.setValidationCondition(r.Recorder)
+	handler.setRenderWarningCondition()
 	if err != nil {
 		logCtx.Error(err, "[handle workflow]")
 		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedWorkflow, err))
@@ -249,7 +251,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if postDispatchApplied || !feature.DefaultMutableFeatureGate.Enabled(features.MultiStageComponentApply) {
 			return nil
 		}
-		if err := handler.applyPostDispatchTraits(logCtx, appParser, appFile); err != nil {
+		err := handler.applyPostDispatchTraits(logCtx, appParser, appFile)
+		// the PostDispatch traits are rendered after the workflow, so their warnings are surfaced again
+		handler.setRenderWarningCondition()
+		if err != nil {
 			logCtx.Error(err, "Failed to apply PostDispatch traits")
 			r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedApply, err))
 			return err
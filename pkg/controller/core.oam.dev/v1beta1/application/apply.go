@@ -18,13 +18,17 @@ package application
 
 import (
 	"context"
+	"fmt"
 	"maps"
 	"slices"
+	"strings"
 	"sync"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,9 +40,11 @@ import (
 	terraforv1beta2 "github.com/oam-dev/terraform-controller/api/v1beta2"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/appfile"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
@@ -63,6 +69,10 @@ type AppHandler struct {
 	services         []common.ApplicationComponentStatus
 	appliedResources []common.ClusterObjectReference
 	deletedResources []common.ClusterObjectReference
+	renderWarnings   []string
+	// renderedComponents the components rendered in this reconcile, validationErrors are the failures of them
+	renderedComponents []string
+	validationErrors   []componentValidationError
 
 	mu sync.Mutex
 }
@@ -225,6 +235,168 @@ func (h *AppHandler) addServiceStatus(cover bool, svcs ...common.ApplicationComp
 	}
 }
 
+// addRenderWarnings records the warnings reported by the definitions while rendering the components
+func (h *AppHandler) addRenderWarnings(warnings ...string) {
+	if len(warnings) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, warning := range warnings {
+		if !slices.Contains(h.renderWarnings, warning) {
+			h.renderWarnings = append(h.renderWarnings, warning)
+		}
+	}
+}
+
+// setRenderWarningCondition surfaces the render warnings into the application conditions, the condition is
+// turned off once the warnings are gone
+func (h *AppHandler) setRenderWarningCondition() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conditionType := condition.ConditionType(common.RenderWarningCondition.String())
+	if len(h.renderWarnings) == 0 {
+		if h.app.Status.GetCondition(conditionType).Status == corev1.ConditionUnknown {
+			return
+		}
+		h.app.Status.SetConditions(condition.Condition{
+			Type:               conditionType,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             condition.ReasonAvailable,
+		})
+		return
+	}
+	h.app.Status.SetConditions(condition.Condition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             condition.ReasonReconcileSuccess,
+		Message:            strings.Join(h.renderWarnings, "; "),
+	})
+}
+
+// maxValidationConditionPaths the max number of parameter paths of each validation error shown in the condition
+const maxValidationConditionPaths = 3
+
+// componentValidationError is the validation failure of a definition rendered for the component, the component is
+// empty if unknown
+type componentValidationError struct {
+	component string
+	err       *definition.CueValidationError
+}
+
+// validationComponentPrefix prefixes the failures of a component in the validation condition
+const validationComponentPrefix = "component "
+
+// message describes the failure in the validation condition, i.e. component web, workload web: invalid parameter.image
+func (e componentValidationError) message() string {
+	msg := fmt.Sprintf("%s %s: validation failed", e.err.EntityType, e.err.EntityName)
+	if paths := e.err.ParameterPaths(maxValidationConditionPaths); len(paths) > 0 {
+		msg = fmt.Sprintf("%s %s: invalid %s", e.err.EntityType, e.err.EntityName, strings.Join(paths, ", "))
+	}
+	if e.component != "" {
+		msg = fmt.Sprintf("%s%s, %s", validationComponentPrefix, e.component, msg)
+	}
+	return msg
+}
+
+// validationMessageComponent returns the component of a failure in the validation condition
+func validationMessageComponent(msg string) string {
+	if !strings.HasPrefix(msg, validationComponentPrefix) {
+		return ""
+	}
+	component, _, found := strings.Cut(strings.TrimPrefix(msg, validationComponentPrefix), ", ")
+	if !found {
+		return ""
+	}
+	return component
+}
+
+// markComponentRendered records that the component is rendered in this reconcile
+func (h *AppHandler) markComponentRendered(compName string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !slices.Contains(h.renderedComponents, compName) {
+		h.renderedComponents = append(h.renderedComponents, compName)
+	}
+}
+
+// RecordValidationError implements definition.ValidationErrorRecorder, it records the validation failures of the
+// definitions while rendering the components
+func (h *AppHandler) RecordValidationError(err *definition.CueValidationError) {
+	h.recordComponentValidationError("", err)
+}
+
+// recordComponentValidationError records the validation failure of a definition rendered for the component
+func (h *AppHandler) recordComponentValidationError(compName string, err *definition.CueValidationError) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.validationErrors {
+		if e.component == compName && e.err.Error() == err.Error() {
+			return
+		}
+	}
+	h.validationErrors = append(h.validationErrors, componentValidationError{component: compName, err: err})
+}
+
+// setValidationCondition surfaces the offending parameter paths of the validation failures into the application
+// conditions. Only the failures of the components rendered in this reconcile are replaced, the ones of the components
+// not rendered, e.g. waiting for a workflow step, are kept, and the condition is turned on once no failure is left.
+// The failures are published as one warning event only when they differ from the ones in the existing condition, so
+// unchanged failures don't emit an event on every reconcile.
+func (h *AppHandler) setValidationCondition(recorder event.Recorder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.renderedComponents) == 0 && len(h.validationErrors) == 0 {
+		return
+	}
+	conditionType := condition.ConditionType(common.ValidationCondition.String())
+	existing := h.app.Status.GetCondition(conditionType)
+	var msgs, errMsgs []string
+	if existing.Status == corev1.ConditionFalse && existing.Message != "" {
+		for _, msg := range strings.Split(existing.Message, "; ") {
+			if component := validationMessageComponent(msg); component != "" && !slices.Contains(h.renderedComponents, component) {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	for _, e := range h.validationErrors {
+		if msg := e.message(); !slices.Contains(msgs, msg) {
+			msgs = append(msgs, msg)
+		}
+		errMsgs = append(errMsgs, e.err.Error())
+	}
+	if len(msgs) == 0 {
+		if existing.Status == corev1.ConditionUnknown {
+			return
+		}
+		h.app.Status.SetConditions(condition.Condition{
+			Type:               conditionType,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             condition.ReasonAvailable,
+		})
+		return
+	}
+	// the kept and the new failures are sorted so that the message is stable across reconciles
+	slices.Sort(msgs)
+	cond := condition.Condition{
+		Type:               conditionType,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             condition.ReasonReconcileError,
+		Message:            strings.Join(msgs, "; "),
+	}
+	if existing.Status == cond.Status && existing.Reason == cond.Reason && existing.Message == cond.Message {
+		return
+	}
+	if len(errMsgs) > 0 {
+		recorder.Event(h.app, event.Warning(types.ReasonFailedValidate, errors.New(strings.Join(errMsgs, "; "))))
+	}
+	h.app.Status.SetConditions(cond)
+}
+
 // collectTraitHealthStatus collect trait health status
 func (h *AppHandler) collectTraitHealthStatus(comp *appfile.Component, tr *appfile.Trait, overrideNamespace string) (common.ApplicationTraitStatus, []*unstructured.Unstructured, error) {
 	defer func(clusterName string) {
@@ -603,6 +775,7 @@ func (h *AppHandler) applyPostDispatchTraits(ctx monitorContext.Context, appPars
 		if err != nil {
 			return errors.WithMessagef(err, "failed to generate manifest for PostDispatch traits of component %s", comp.Name)
 		}
+		h.addRenderWarnings(manifest.Warnings...)
 
 		// Render traits
 		_, readyTraits, err := renderComponentsAndTraits(manifest, h.currentAppRev, svc.Cluster, svc.Namespace)
@@ -25,10 +25,12 @@ import (
 
 	"github.com/oam-dev/kubevela/pkg/oam/testutil"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	terraformtypes "github.com/oam-dev/terraform-controller/api/types"
 	terraformapi "github.com/oam-dev/terraform-controller/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,7 +42,10 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	velatypes "github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
 
@@ -288,6 +293,171 @@ func TestDeleteAppliedResourceFunc(t *testing.T) {
 	}
 }
 
+func TestRenderWarningCondition(t *testing.T) {
+	h := AppHandler{app: &v1beta1.Application{}}
+	h.setRenderWarningCondition()
+	if len(h.app.Status.Conditions) != 0 {
+		t.Errorf("condition should not be set without warnings")
+	}
+
+	h.addRenderWarnings("trait scaler: replicas is deprecated", "trait scaler: replicas is deprecated")
+	h.addRenderWarnings("component web: port is unused")
+	h.setRenderWarningCondition()
+	cond := h.app.Status.GetCondition(condition.ConditionType(common.RenderWarningCondition.String()))
+	if cond.Status != corev1.ConditionTrue {
+		t.Errorf("condition status mismatch actually %s", cond.Status)
+	}
+	if cond.Message != "trait scaler: replicas is deprecated; component web: port is unused" {
+		t.Errorf("condition message mismatch actually %s", cond.Message)
+	}
+
+	h.renderWarnings = nil
+	h.setRenderWarningCondition()
+	cond = h.app.Status.GetCondition(condition.ConditionType(common.RenderWarningCondition.String()))
+	if cond.Status != corev1.ConditionFalse {
+		t.Errorf("condition status mismatch actually %s", cond.Status)
+	}
+}
+
+type fakeEventRecorder struct {
+	events []event.Event
+}
+
+func (r *fakeEventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *fakeEventRecorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func TestValidationCondition(t *testing.T) {
+	h := AppHandler{app: &v1beta1.Application{}}
+	recorder := &fakeEventRecorder{}
+	h.setValidationCondition(recorder)
+	if len(h.app.Status.Conditions) != 0 || len(recorder.events) != 0 {
+		t.Errorf("condition and event should not be set without validation errors")
+	}
+
+	verr := &definition.CueValidationError{
+		Prefix:     "validation failed for",
+		EntityType: "workload",
+		EntityName: "web",
+		ParameterErrors: []definition.FieldError{
+			{Path: "parameter.image", Message: "parameter.image: incomplete value string"},
+			{Path: "parameter.port", Message: "parameter.port: invalid value 0 (out of bound >=1)"},
+		},
+	}
+	if !definition.RecordValidationError(&h, errors.WithMessage(verr, "GenerateComponentManifest")) {
+		t.Errorf("validation error should be recorded")
+	}
+	h.RecordValidationError(verr)
+	if definition.RecordValidationError(&h, errors.New("other error")) {
+		t.Errorf("only validation error should be recorded")
+	}
+	h.setValidationCondition(recorder)
+	cond := h.app.Status.GetCondition(condition.ConditionType(common.ValidationCondition.String()))
+	if cond.Status != corev1.ConditionFalse {
+		t.Errorf("condition status mismatch actually %s", cond.Status)
+	}
+	if cond.Message != "workload web: invalid parameter.image, parameter.port" {
+		t.Errorf("condition message mismatch actually %s", cond.Message)
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Type != event.TypeWarning || recorder.events[0].Reason != velatypes.ReasonFailedValidate {
+		t.Errorf("warning event mismatch actually %v", recorder.events)
+	}
+
+	// the same failures in the next reconcile don't emit another event
+	h.setValidationCondition(recorder)
+	if len(recorder.events) != 1 {
+		t.Errorf("unchanged validation errors should not emit events actually %v", recorder.events)
+	}
+
+	// the failures of several entities are aggregated into one event
+	h.RecordValidationError(&definition.CueValidationError{
+		Prefix:     "validation failed for",
+		EntityType: "trait",
+		EntityName: "scaler",
+		ParameterErrors: []definition.FieldError{
+			{Path: "parameter.replicas", Message: "parameter.replicas: conflicting values 1 and \"1\""},
+		},
+	})
+	h.setValidationCondition(recorder)
+	cond = h.app.Status.GetCondition(condition.ConditionType(common.ValidationCondition.String()))
+	if cond.Message != "trait scaler: invalid parameter.replicas; workload web: invalid parameter.image, parameter.port" {
+		t.Errorf("condition message mismatch actually %s", cond.Message)
+	}
+	if len(recorder.events) != 2 {
+		t.Errorf("changed validation errors should emit one event actually %v", recorder.events)
+	}
+
+	h.validationErrors = nil
+	h.markComponentRendered("web")
+	h.setValidationCondition(recorder)
+	cond = h.app.Status.GetCondition(condition.ConditionType(common.ValidationCondition.String()))
+	if cond.Status != corev1.ConditionTrue {
+		t.Errorf("condition status mismatch actually %s", cond.Status)
+	}
+	h.RecordValidationError(verr)
+	h.setValidationCondition(recorder)
+	if len(recorder.events) != 3 {
+		t.Errorf("recurring validation errors should emit an event actually %v", recorder.events)
+	}
+}
+
+func TestValidationConditionOfRenderedComponents(t *testing.T) {
+	conditionType := condition.ConditionType(common.ValidationCondition.String())
+	recorder := &fakeEventRecorder{}
+	newHandler := func(app *v1beta1.Application) *AppHandler {
+		return &AppHandler{app: app}
+	}
+	failure := func(entityType, entityName, path string) *definition.CueValidationError {
+		return &definition.CueValidationError{
+			Prefix:          "validation failed for",
+			EntityType:      entityType,
+			EntityName:      entityName,
+			ParameterErrors: []definition.FieldError{{Path: path, Message: path + ": incomplete value string"}},
+		}
+	}
+
+	app := &v1beta1.Application{}
+	h := newHandler(app)
+	h.markComponentRendered("web")
+	h.markComponentRendered("db")
+	h.recordComponentValidationError("web", failure("workload", "web", "parameter.image"))
+	h.recordComponentValidationError("db", failure("trait", "storage", "parameter.size"))
+	h.setValidationCondition(recorder)
+	cond := app.Status.GetCondition(conditionType)
+	if cond.Message != "component db, trait storage: invalid parameter.size; component web, workload web: invalid parameter.image" {
+		t.Errorf("condition message mismatch actually %s", cond.Message)
+	}
+
+	// nothing rendered in the reconcile, e.g. waiting for a workflow step, the condition is kept
+	newHandler(app).setValidationCondition(recorder)
+	if cond = app.Status.GetCondition(conditionType); cond.Status != corev1.ConditionFalse {
+		t.Errorf("condition should be kept without renderings actually %s", cond.Status)
+	}
+
+	// only the failures of the rendered components are cleared
+	h = newHandler(app)
+	h.markComponentRendered("web")
+	h.setValidationCondition(recorder)
+	cond = app.Status.GetCondition(conditionType)
+	if cond.Status != corev1.ConditionFalse || cond.Message != "component db, trait storage: invalid parameter.size" {
+		t.Errorf("condition mismatch actually %s: %s", cond.Status, cond.Message)
+	}
+	if len(recorder.events) != 1 {
+		t.Errorf("cleared failures should not emit events actually %v", recorder.events)
+	}
+
+	h = newHandler(app)
+	h.markComponentRendered("db")
+	h.setValidationCondition(recorder)
+	if cond = app.Status.GetCondition(conditionType); cond.Status != corev1.ConditionTrue {
+		t.Errorf("condition status mismatch actually %s", cond.Status)
+	}
+}
+
 var _ = Describe("Test Application health check", func() {
 	const (
 		timeout  = time.Second * 10
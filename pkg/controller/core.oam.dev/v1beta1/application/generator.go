@@ -48,12 +48,14 @@ import (
 	"github.com/oam-dev/kubevela/pkg/config"
 	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1beta1/application/assemble"
 	ctrlutil "github.com/oam-dev/kubevela/pkg/controller/utils"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
 	"github.com/oam-dev/kubevela/pkg/workflow/providers"
 	oamprovidertypes "github.com/oam-dev/kubevela/pkg/workflow/providers/types"
@@ -82,6 +84,11 @@ func (h *AppHandler) GenerateApplicationSteps(ctx monitorContext.Context,
 		oam.LabelAppName:      app.Name,
 		oam.LabelAppNamespace: app.Namespace,
 	}
+	// the components are rendered by the workflow steps across reconciles, the outputs of the dependencies dispatched
+	// by the previous reconciles are loaded from the resource trackers
+	af.DispatchedComponentLoader = func(compName string) (*types.ComponentManifest, error) {
+		return h.loadDispatchedComponent(ctx.GetContext(), compName)
+	}
 	pCtx := velaprocess.NewContext(generateContextDataFromApp(app, appRev.Name))
 	ctxWithRuntimeParams := oamprovidertypes.WithRuntimeParams(ctx.GetContext(), oamprovidertypes.RuntimeParams{
 		ComponentApply:       h.applyComponentFunc(appParser, af),
@@ -412,6 +419,7 @@ func (h *AppHandler) prepareWorkloadAndManifests(ctx context.Context,
 		}
 	}
 
+	h.markComponentRendered(comp.Name)
 	manifest, err := af.GenerateComponentManifest(wl, func(ctxData *velaprocess.ContextData) {
 		if ns := componentNamespaceFromContext(ctx); ns != "" {
 			ctxData.Namespace = ns
@@ -454,8 +462,13 @@ func (h *AppHandler) prepareWorkloadAndManifests(ctx context.Context,
 		}
 	})
 	if err != nil {
+		var verr *definition.CueValidationError
+		if errors.As(err, &verr) {
+			h.recordComponentValidationError(comp.Name, verr)
+		}
 		return nil, nil, errors.WithMessage(err, "GenerateComponentManifest")
 	}
+	h.addRenderWarnings(manifest.Warnings...)
 	if err := af.SetOAMContract(manifest); err != nil {
 		return nil, nil, errors.WithMessage(err, "SetOAMContract")
 	}
@@ -508,6 +521,41 @@ func componentOutputsConsumed(comp common.ApplicationComponent, components []com
 	return false
 }
 
+// loadDispatchedComponent rebuilds the manifest of the component from the resources recorded in the resource trackers
+// of the application, i.e. the resources dispatched by the previous reconciles. It returns nil if the workload of the
+// component is not recorded.
+func (h *AppHandler) loadDispatchedComponent(ctx context.Context, compName string) (*types.ComponentManifest, error) {
+	rootRT, currentRT, _, _, err := resourcetracker.ListApplicationResourceTrackers(multicluster.ContextInLocalCluster(ctx), h.Client, h.app)
+	if err != nil {
+		return nil, err
+	}
+	cm := &types.ComponentManifest{Name: compName}
+	for _, rt := range []*v1beta1.ResourceTracker{rootRT, currentRT} {
+		if rt == nil {
+			continue
+		}
+		for _, mr := range rt.Spec.ManagedResources {
+			if mr.Component != compName || mr.Deleted {
+				continue
+			}
+			obj, err := mr.ToUnstructuredWithData()
+			if err != nil {
+				// the resource is recorded without its data
+				continue
+			}
+			if obj.GetLabels()[oam.LabelOAMResourceType] == oam.ResourceTypeWorkload {
+				cm.ComponentOutput = obj
+			} else {
+				cm.ComponentOutputsAndTraits = append(cm.ComponentOutputsAndTraits, obj)
+			}
+		}
+	}
+	if cm.ComponentOutput == nil {
+		return nil, nil
+	}
+	return cm, nil
+}
+
 func checkSkipApplyWorkload(comp *appfile.Component) {
 	for _, trait := range comp.Traits {
 		if trait.FullTemplate.TraitDefinition.Spec.ManageWorkload {
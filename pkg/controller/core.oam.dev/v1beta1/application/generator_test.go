@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/json"
 	"strconv"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -26,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
 	wfTypesv1alpha1 "github.com/kubevela/pkg/apis/oam/v1alpha1"
@@ -33,9 +35,62 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	oamcore "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
 
+func TestLoadDispatchedComponent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := oamcore.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	app := &oamcore.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 2}}
+	managedResource := func(kind, name, trait, raw string) oamcore.ManagedResource {
+		mr := oamcore.ManagedResource{
+			ClusterObjectReference: common.ClusterObjectReference{ObjectReference: corev1.ObjectReference{
+				APIVersion: "v1", Kind: kind, Name: name, Namespace: "default",
+			}},
+			OAMObjectReference: common.OAMObjectReference{Component: "backend", Trait: trait},
+		}
+		if raw != "" {
+			mr.Data = &runtime.RawExtension{Raw: []byte(raw)}
+		}
+		return mr
+	}
+	rt := &oamcore.ResourceTracker{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-v2-default", Labels: map[string]string{
+			oam.LabelAppName:      "app",
+			oam.LabelAppNamespace: "default",
+		}},
+		Spec: oamcore.ResourceTrackerSpec{
+			Type:                  oamcore.ResourceTrackerTypeVersioned,
+			ApplicationGeneration: 2,
+			ManagedResources: []oamcore.ManagedResource{
+				managedResource("ConfigMap", "backend", "", `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"backend","labels":{"app.oam.dev/resourceType":"WORKLOAD"}},"data":{"key":"b"}}`),
+				managedResource("Secret", "backend-secret", "", `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"backend-secret","labels":{"trait.oam.dev/resource":"secret"}}}`),
+				managedResource("Service", "backend-svc", "expose", ""),
+			},
+		},
+	}
+	h := &AppHandler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(rt).Build(), app: app}
+
+	cm, err := h.loadDispatchedComponent(context.Background(), "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm == nil || cm.ComponentOutput == nil || cm.ComponentOutput.GetName() != "backend" {
+		t.Fatalf("the dispatched workload should be loaded actually %v", cm)
+	}
+	// the resources recorded without data are skipped
+	if len(cm.ComponentOutputsAndTraits) != 1 || cm.ComponentOutputsAndTraits[0].GetLabels()[oam.TraitResource] != "secret" {
+		t.Errorf("the dispatched auxiliaries mismatch actually %v", cm.ComponentOutputsAndTraits)
+	}
+
+	if cm, err = h.loadDispatchedComponent(context.Background(), "frontend"); err != nil || cm != nil {
+		t.Errorf("the component not dispatched should not be loaded actually %v, %v", cm, err)
+	}
+}
+
 var _ = Describe("Test Application workflow generator", func() {
 	var namespaceName string
 	var ns corev1.Namespace
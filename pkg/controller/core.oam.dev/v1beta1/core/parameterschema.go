@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
+	"github.com/oam-dev/kubevela/pkg/oam/util"
+)
+
+// ParameterSchemaWatcher keeps the parameter schema catalog in sync with the ComponentDefinitions and
+// TraitDefinitions watched by the informers. Unlike the definition controllers which only run on the leader, it
+// runs on every replica as every replica serves the catalog.
+type ParameterSchemaWatcher struct {
+	Catalog            *definition.ParameterSchemaCatalog
+	ControllerVersion  string
+	IgnoreDefNoCtrlReq bool
+}
+
+// Watch refreshes the catalog on the changes of the definitions of the informer
+func (w *ParameterSchemaWatcher) Watch(ctx context.Context, informer ctrlcache.Informer) error {
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.sync(ctx, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			w.sync(ctx, obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if kind, def, _ := parameterSchemaSource(obj); def != nil {
+				w.Catalog.Delete(kind, def.GetNamespace(), def.GetName())
+			}
+		},
+	})
+	return err
+}
+
+// sync sets the schema of the definition, the schema is removed if the definition is skipped by this controller,
+// is not a CUE schematic any more or its schema can't be generated
+func (w *ParameterSchemaWatcher) sync(ctx context.Context, obj interface{}) {
+	kind, def, schematic := parameterSchemaSource(obj)
+	if def == nil {
+		return
+	}
+	if !MatchControllerRequirement(def, w.ControllerVersion, w.IgnoreDefNoCtrlReq) || schematic == nil || schematic.CUE == nil {
+		w.Catalog.Delete(kind, def.GetNamespace(), def.GetName())
+		return
+	}
+	if err := w.Catalog.Set(ctx, kind, def.GetNamespace(), def.GetName(), schematic.CUE.Template); err != nil {
+		klog.ErrorS(err, "Could not generate the parameter schema", "kind", kind, "definition", klog.KObj(def))
+		w.Catalog.Delete(kind, def.GetNamespace(), def.GetName())
+	}
+}
+
+func parameterSchemaSource(obj interface{}) (string, util.ConditionedObject, *common.Schematic) {
+	switch def := obj.(type) {
+	case *v1beta1.ComponentDefinition:
+		return v1beta1.ComponentDefinitionKind, def, def.Spec.Schematic
+	case *v1beta1.TraitDefinition:
+		return v1beta1.TraitDefinitionKind, def, def.Spec.Schematic
+	default:
+		return "", nil, nil
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+func TestParameterSchemaWatcher(t *testing.T) {
+	ctx := context.Background()
+	w := &ParameterSchemaWatcher{Catalog: definition.NewParameterSchemaCatalog(), ControllerVersion: "v1.10.0"}
+	name := definition.ParameterSchemaName(v1beta1.TraitDefinitionKind, "vela-system", "scaler")
+	trait := &v1beta1.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaler", Namespace: "vela-system"},
+		Spec: v1beta1.TraitDefinitionSpec{Schematic: &common.Schematic{CUE: &common.CUE{Template: `
+parameter: replicas: *1 | int
+patch: spec: replicas: parameter.replicas
+`}}},
+	}
+	w.sync(ctx, trait)
+	require.Contains(t, w.Catalog.Document().Components.Schemas, name)
+
+	// the schema failing to generate is removed
+	broken := trait.DeepCopy()
+	broken.Spec.Schematic.CUE.Template = `parameter: {`
+	w.sync(ctx, broken)
+	require.NotContains(t, w.Catalog.Document().Components.Schemas, name)
+
+	// the definition which is not a CUE schematic any more is removed
+	w.sync(ctx, trait)
+	nonCUE := trait.DeepCopy()
+	nonCUE.Spec.Schematic = nil
+	w.sync(ctx, nonCUE)
+	require.NotContains(t, w.Catalog.Document().Components.Schemas, name)
+
+	// the definition skipped by the controller is removed
+	w.sync(ctx, trait)
+	skipped := trait.DeepCopy()
+	skipped.Annotations = map[string]string{oam.AnnotationControllerRequirement: "v1.9.0"}
+	w.sync(ctx, skipped)
+	require.NotContains(t, w.Catalog.Document().Components.Schemas, name)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/kubevela/pkg/cue/cuex"
 
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
@@ -74,7 +75,7 @@ func GetParameters(templateStr string) ([]types.Parameter, error) {
 
 // GetParametersWithCuex get parameter from cue template with cuex support for package imports
 func GetParametersWithCuex(ctx context.Context, templateStr string) ([]types.Parameter, error) {
-	template, err := cuex.DefaultCompiler.Get().CompileStringWithOptions(
+	template, err := definition.GetCompiler(templateStr).CompileStringWithOptions(
 		ctx,
 		templateStr+BaseTemplate,
 		cuex.DisableResolveProviderFunctions{},
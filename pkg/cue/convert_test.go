@@ -185,6 +185,21 @@ parameter: {
 	assert.True(t, foundName, "Should find 'name' parameter")
 	assert.True(t, foundConfig, "Should find 'config' parameter")
 
+	// Template importing the packages registered for definitions
+	params, err = GetParametersWithCuex(ctx, `
+import "vela/cel"
+
+check: cel.#Eval & {
+	$params: {
+		expression: "size(name) < 20"
+		variables: name: parameter.name
+	}
+}
+parameter: name: string
+`)
+	assert.NoError(t, err, "Should compile template with registered package imports")
+	assert.Equal(t, []types.Parameter{{Name: "name", Required: true, Default: "", Type: cue.StringKind}}, params)
+
 	// Test 3: Template without parameter field
 	data, _ = os.ReadFile("testdata/workloads/empty.cue")
 	params, err = GetParametersWithCuex(ctx, string(data))
@@ -0,0 +1,20 @@
+package cel
+
+#Eval: {
+	#do:       "eval"
+	#provider: "cel"
+
+	// +usage=The params of this action
+	$params: {
+		// +usage=The CEL expression to evaluate, such as: size(name) < 20
+		expression: string
+		// +usage=The variables referred by the expression
+		variables?: {...}
+	}
+	// +usage=The result of this action, will be filled with the evaluation result after the action is executed
+	$returns?: {
+		// +usage=The result of the expression
+		result: _
+	}
+	...
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/kubevela/pkg/cue/cuex/providers"
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+	"github.com/kubevela/pkg/util/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// EvalVars is the vars for evaluating a CEL expression
+type EvalVars struct {
+	Expression string                 `json:"expression"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+}
+
+// EvalResult is the result of the CEL expression
+type EvalResult struct {
+	Result interface{} `json:"result"`
+}
+
+// EvalParams is the params for eval
+type EvalParams providers.Params[EvalVars]
+
+// EvalReturns returned struct for eval
+type EvalReturns providers.Returns[EvalResult]
+
+// libraries the extension libraries available to the expressions, aligned with the ones enabled by Kubernetes
+var libraries = []cel.EnvOption{ext.Strings(), ext.Lists(), ext.Sets(), ext.Math(), ext.Encoders()}
+
+// Eval evaluates the CEL expression, the variables are declared as dynamically typed top-level identifiers
+func Eval(_ context.Context, evalParams *EvalParams) (*EvalReturns, error) {
+	params := evalParams.Params
+	names := make([]string, 0, len(params.Variables))
+	for name := range params.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	opts := append([]cel.EnvOption{}, libraries...)
+	for _, name := range names {
+		opts = append(opts, cel.Variable(name, cel.DynType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(params.Expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("failed to compile CEL expression %q: %w", params.Expression, iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CEL program %q: %w", params.Expression, err)
+	}
+	vars := params.Variables
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate CEL expression %q: %w", params.Expression, err)
+	}
+	native, err := out.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported result type %s of CEL expression %q: %w", out.Type(), params.Expression, err)
+	}
+	bs, err := protojson.Marshal(native.(*structpb.Value))
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err = json.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+	return &EvalReturns{Returns: EvalResult{Result: result}}, nil
+}
+
+// ProviderName .
+const ProviderName = "cel"
+
+//go:embed cel.cue
+var template string
+
+// Package .
+var Package = runtime.Must(cuexruntime.NewInternalPackage(ProviderName, template, map[string]cuexruntime.ProviderFn{
+	"eval": cuexruntime.GenericProviderFn[EvalParams, EvalReturns](Eval),
+}))
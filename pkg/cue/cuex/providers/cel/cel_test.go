@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name       string
+		expression string
+		variables  map[string]interface{}
+		expected   interface{}
+		err        string
+	}{
+		{
+			name:       "bool result",
+			expression: `size(name) < 20`,
+			variables:  map[string]interface{}{"name": "my-app"},
+			expected:   true,
+		},
+		{
+			name:       "no variables",
+			expression: `"a" + "b"`,
+			expected:   "ab",
+		},
+		{
+			name:       "structured result",
+			expression: `{"replicas": spec.replicas * 2, "tags": spec.tags.filter(t, t != "b")}`,
+			variables:  map[string]interface{}{"spec": map[string]interface{}{"replicas": 2, "tags": []interface{}{"a", "b"}}},
+			expected:   map[string]interface{}{"replicas": float64(4), "tags": []interface{}{"a"}},
+		},
+		{
+			name:       "compile error",
+			expression: `size(name`,
+			variables:  map[string]interface{}{"name": "my-app"},
+			err:        "failed to compile CEL expression",
+		},
+		{
+			name:       "undeclared variable",
+			expression: `size(name) < 20`,
+			err:        "failed to compile CEL expression",
+		},
+		{
+			name:       "evaluation error",
+			expression: `name.missing`,
+			variables:  map[string]interface{}{"name": map[string]interface{}{}},
+			err:        "failed to evaluate CEL expression",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := Eval(ctx, &EvalParams{Params: EvalVars{Expression: tc.expression, Variables: tc.variables}})
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ret.Returns.Result)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
+)
+
+var (
+	// ValidationErrorCacheSize the max number of validation errors kept in the validation error cache
+	ValidationErrorCacheSize = 1024
+	// ValidationErrorCacheTTL how long a validation error is reused for the same template and parameters
+	ValidationErrorCacheTTL = time.Minute
+	// ParseCacheSize the max number of parsed templates kept in the parse cache
+	ParseCacheSize = 1024
+)
+
+func hashString(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+// compileTemplate compiles the template together with its parameter and base context. The compiled value is built
+// on every call: CUE values are not safe for concurrent use and the providers of the template must read the latest
+// external state, so only the syntax trees of the templates are cached, see parseTemplate.
+func compileTemplate(ctx context.Context, template, params, baseContext string) (cue.Value, error) {
+	content := strings.Join([]string{template, params, baseContext}, "\n")
+	key := hashString(template)
+	if compiler, mocked := getMockCompiler(ctx, template); mocked {
+		return compileString(ctx, compiler, key, content)
+	}
+	return compileString(ctx, GetCompiler(template), key, content)
+}
+
+// validationErrorCacheKey identifies the rendering of a definition with the given template, parameters and base
+// context
+type validationErrorCacheKey struct {
+	entityType string
+	entityName string
+	template   string
+	params     string
+	context    string
+}
+
+// newValidationErrorCacheKey builds the key of a rendering, the params must have the parameter defaults applied and
+// the baseContext is the one the template is compiled with, so that the renderings of different applications or
+// namespaces don't share their errors
+func newValidationErrorCacheKey(entityType, entityName, template string, params interface{}, baseContext string) validationErrorCacheKey {
+	bt, _ := json.Marshal(params)
+	return validationErrorCacheKey{
+		entityType: entityType,
+		entityName: entityName,
+		template:   hashString(template),
+		params:     hashString(string(bt)),
+		context:    hashString(baseContext),
+	}
+}
+
+type validationErrorCacheEntry struct {
+	err      *CueValidationError
+	expireAt time.Time
+}
+
+// validationErrorCache is the negative cache of the renderings, it keeps the validation errors extracted from the
+// failed renderings until they expire
+type validationErrorCache struct {
+	mu      sync.Mutex
+	entries map[validationErrorCacheKey]*validationErrorCacheEntry
+}
+
+var defaultValidationErrorCache = &validationErrorCache{entries: map[validationErrorCacheKey]*validationErrorCacheEntry{}}
+
+// get returns a deep copy of the cached error, so that the callers can modify it, e.g. setting the verbosity,
+// without racing with each other
+func (c *validationErrorCache) get(key validationErrorCacheKey) (*CueValidationError, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(entry.expireAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.err.deepCopy(), true
+}
+
+func (c *validationErrorCache) add(key validationErrorCacheKey, err *CueValidationError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= ValidationErrorCacheSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expireAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= ValidationErrorCacheSize {
+		c.evictEarliest()
+	}
+	c.entries[key] = &validationErrorCacheEntry{err: err, expireAt: now.Add(ValidationErrorCacheTTL)}
+}
+
+// evictEarliest drop the entry expiring first, must be called with the lock held
+func (c *validationErrorCache) evictEarliest() {
+	var earliestKey validationErrorCacheKey
+	var earliest *validationErrorCacheEntry
+	for k, entry := range c.entries {
+		if earliest == nil || entry.expireAt.Before(earliest.expireAt) {
+			earliestKey, earliest = k, entry
+		}
+	}
+	if earliest != nil {
+		delete(c.entries, earliestKey)
+	}
+}
+
+func (c *validationErrorCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[validationErrorCacheKey]*validationErrorCacheEntry{}
+}
+
+// newRenderValidationError builds the validation error of a failed rendering. If the EnableValidationErrorCache
+// feature is enabled, the error extracted for the same template, parameters and base context is reused until it
+// expires. The cached error is decorated with the template before being added and never modified afterwards.
+func newRenderValidationError(key validationErrorCacheKey, template string, validationErr error, userErrors []string, val *cue.Value) *CueValidationError {
+	if !feature.DefaultMutableFeatureGate.Enabled(features.EnableValidationErrorCache) {
+		return NewCueValidationError(validationErr, "validation failed for", key.entityType, key.entityName, userErrors, val)
+	}
+	if verr, found := defaultValidationErrorCache.get(key); found {
+		metrics.ValidationErrorCacheCounter.WithLabelValues("hit").Inc()
+		return verr
+	}
+	metrics.ValidationErrorCacheCounter.WithLabelValues("miss").Inc()
+	verr := NewCueValidationError(validationErr, "validation failed for", key.entityType, key.entityName, userErrors, val)
+	verr.decorate(template)
+	defaultValidationErrorCache.add(key, verr.deepCopy())
+	return verr
+}
+
+type parseCacheEntry struct {
+	key  string
+	file *ast.File
+	err  error
+}
+
+// parseCache memoizes the syntax trees of definition templates by the hash of their content, so a new revision of
+// a definition is parsed once and the stale entries are evicted as the least recently used
+type parseCache struct {
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+func newParseCache() *parseCache {
+	return &parseCache{lru: list.New(), entries: map[string]*list.Element{}}
+}
+
+var defaultParseCache = newParseCache()
+
+func (c *parseCache) get(key string) (*parseCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*parseCacheEntry), true
+}
+
+func (c *parseCache) add(key string, file *ast.File, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, found := c.entries[key]; found {
+		c.lru.MoveToFront(elem)
+		elem.Value = &parseCacheEntry{key: key, file: file, err: err}
+		return
+	}
+	for c.lru.Len() > 0 && c.lru.Len() >= ParseCacheSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parseCacheEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&parseCacheEntry{key: key, file: file, err: err})
+}
+
+func (c *parseCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.entries = map[string]*list.Element{}
+}
+
+// parseTemplate parses the template into a syntax tree, the result including the syntax error is cached by the
+// hash of the template. The returned file is shared by the callers and must not be modified.
+func parseTemplate(template string) (*ast.File, error) {
+	key := hashString(template)
+	if entry, found := defaultParseCache.get(key); found {
+		return entry.file, entry.err
+	}
+	f, err := parser.ParseFile("-", template)
+	defaultParseCache.add(key, f, err)
+	return f, err
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	template := `output: replicas: parameter.replicas`
+	v1, err := compileTemplate(context.Background(), template, `parameter: replicas: 1`, `context: name: "a"`)
+	require.NoError(t, err)
+	v2, err := compileTemplate(context.Background(), template, `parameter: replicas: 1`, `context: name: "a"`)
+	require.NoError(t, err)
+	// the values of the same inputs are built separately so that they can be used concurrently
+	require.NotEqual(t, v1.Context(), v2.Context())
+	v3, err := compileTemplate(context.Background(), template, `parameter: replicas: 2`, `context: name: "a"`)
+	require.NoError(t, err)
+	replicas, err := v3.LookupPath(value.FieldPath("output.replicas")).Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), replicas)
+}
+
+func TestValidationErrorCache(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.EnableValidationErrorCache, true)
+	defaultValidationErrorCache.reset()
+	t.Cleanup(defaultValidationErrorCache.reset)
+
+	hits := testutil.ToFloat64(metrics.ValidationErrorCacheCounter.WithLabelValues("hit"))
+	misses := testutil.ToFloat64(metrics.ValidationErrorCacheCounter.WithLabelValues("miss"))
+
+	template := `
+parameter: replicas: int & >=1
+output: {apiVersion: "apps/v1", kind: "Deployment", spec: replicas: parameter.replicas}
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	wd := NewWorkloadAbstractEngine("comp", WithErrorVerbosity(ErrorVerbosityVerbose))
+	err1 := wd.Complete(ctx, template, map[string]interface{}{"replicas": 0})
+	require.Error(t, err1)
+	err2 := wd.Complete(ctx, template, map[string]interface{}{"replicas": 0})
+	require.Error(t, err2)
+	require.Equal(t, err1.Error(), err2.Error())
+	require.Contains(t, err2.Error(), "at line 2, column 28")
+	require.Error(t, wd.Complete(ctx, template, map[string]interface{}{"replicas": -1}))
+	require.NoError(t, wd.Complete(ctx, template, map[string]interface{}{"replicas": 1}))
+	// the renderings in another namespace don't share the errors
+	otherCtx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "other"})
+	require.Error(t, wd.Complete(otherCtx, template, map[string]interface{}{"replicas": 0}))
+
+	require.Equal(t, hits+1, testutil.ToFloat64(metrics.ValidationErrorCacheCounter.WithLabelValues("hit")))
+	require.Equal(t, misses+3, testutil.ToFloat64(metrics.ValidationErrorCacheCounter.WithLabelValues("miss")))
+
+	// decorating the returned errors doesn't modify the cached one
+	var verr *CueValidationError
+	require.ErrorAs(t, err2, &verr)
+	verr.ParameterErrors[0].Snippet = "modified"
+	err3 := wd.Complete(ctx, template, map[string]interface{}{"replicas": 0})
+	require.Equal(t, err1.Error(), err3.Error())
+}
+
+func TestValidationErrorCacheExpiration(t *testing.T) {
+	c := &validationErrorCache{entries: map[validationErrorCacheKey]*validationErrorCacheEntry{}}
+	size, ttl := ValidationErrorCacheSize, ValidationErrorCacheTTL
+	ValidationErrorCacheSize = 2
+	t.Cleanup(func() { ValidationErrorCacheSize, ValidationErrorCacheTTL = size, ttl })
+
+	keyA := newValidationErrorCacheKey("trait", "a", "template", nil, `context: namespace: "default"`)
+	keyB := newValidationErrorCacheKey("trait", "b", "template", map[string]interface{}{"k": "v"}, `context: namespace: "default"`)
+	keyC := newValidationErrorCacheKey("trait", "c", "template", nil, `context: namespace: "default"`)
+	require.NotEqual(t, keyA, newValidationErrorCacheKey("trait", "a", "template", nil, `context: namespace: "other"`))
+	c.add(keyA, &CueValidationError{EntityName: "a", ParameterErrors: []FieldError{{Path: "parameter.k"}}})
+	c.add(keyB, &CueValidationError{EntityName: "b"})
+	verr, found := c.get(keyA)
+	require.True(t, found)
+	require.Equal(t, "a", verr.EntityName)
+	verr.ParameterErrors[0].Path = "parameter.modified"
+	verr, found = c.get(keyA)
+	require.True(t, found)
+	require.Equal(t, "parameter.k", verr.ParameterErrors[0].Path)
+
+	// the entry expiring first is evicted when the cache is full
+	c.add(keyC, &CueValidationError{EntityName: "c"})
+	_, found = c.get(keyA)
+	require.False(t, found)
+
+	ValidationErrorCacheTTL = -time.Second
+	c.add(keyA, &CueValidationError{EntityName: "a"})
+	_, found = c.get(keyA)
+	require.False(t, found)
+}
+
+func TestParseTemplateCache(t *testing.T) {
+	defaultParseCache.reset()
+	t.Cleanup(defaultParseCache.reset)
+
+	f, err := parseTemplate(`parameterDefaults: config: "defaults"`)
+	require.NoError(t, err)
+	cached, err := parseTemplate(`parameterDefaults: config: "defaults"`)
+	require.NoError(t, err)
+	require.Same(t, f, cached)
+	require.Equal(t, &ParameterDefaultsRef{Config: "defaults", Namespace: "vela-system"},
+		GetParameterDefaultsRef(`parameterDefaults: config: "defaults"`))
+
+	_, err = parseTemplate(`parameter: {`)
+	require.Error(t, err)
+	_, err = parseTemplate(`parameter: {`)
+	require.Error(t, err)
+	require.Len(t, defaultParseCache.entries, 2)
+
+	origSize := ParseCacheSize
+	ParseCacheSize = 2
+	t.Cleanup(func() { ParseCacheSize = origSize })
+	_, _ = parseTemplate(`parameter: {`)
+	_, err = parseTemplate(`output: {}`)
+	require.NoError(t, err)
+	require.Len(t, defaultParseCache.entries, 2)
+	_, found := defaultParseCache.entries[hashString(`parameterDefaults: config: "defaults"`)]
+	require.False(t, found)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	cuexv1alpha1 "github.com/kubevela/pkg/apis/cue/v1alpha1"
+	"github.com/kubevela/pkg/cue/cuex"
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+	"github.com/kubevela/pkg/util/singleton"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/oam-dev/kubevela/pkg/cue/cuex/providers/cel"
+)
+
+var (
+	packagesMu sync.RWMutex
+	// registeredPackages the cuex packages registered for definitions, indexed by import path
+	registeredPackages = map[string]cuexruntime.Package{}
+	// packageCompilers the compilers built for each combination of registered packages
+	packageCompilers = map[string]*cuex.Compiler{}
+	// packageLoaders the loaders of the allowlisted packages which are registered on first import, indexed by
+	// import path
+	packageLoaders = map[string]PackageLoader{}
+)
+
+// PackageLoader builds a cuex package on demand
+type PackageLoader func() (cuexruntime.Package, error)
+
+func init() {
+	// the CEL package is only loaded by the templates importing vela/cel
+	RegisterPackage(cel.Package)
+}
+
+// RegisterPackage registers a cuex package for definitions. The package is not added to the default compiler,
+// instead the templates importing it will be compiled by a dedicated compiler which loads the package on top of
+// the packages of the default compiler.
+func RegisterPackage(pkgs ...cuexruntime.Package) {
+	packagesMu.Lock()
+	defer packagesMu.Unlock()
+	for _, pkg := range pkgs {
+		registeredPackages[pkg.GetPath()] = pkg
+	}
+	packageCompilers = map[string]*cuex.Compiler{}
+}
+
+// UnregisterPackage removes the registered cuex packages by their import paths
+func UnregisterPackage(paths ...string) {
+	packagesMu.Lock()
+	defer packagesMu.Unlock()
+	for _, path := range paths {
+		delete(registeredPackages, path)
+	}
+	packageCompilers = map[string]*cuex.Compiler{}
+}
+
+// RegisterPackageLoader allowlists a package for definitions without building it. The package is built and
+// registered the first time a template imports it, so that new provider packages can be enabled without a
+// restart of the controller. A loader failing is retried on the next import.
+func RegisterPackageLoader(path string, loader PackageLoader) {
+	packagesMu.Lock()
+	defer packagesMu.Unlock()
+	packageLoaders[path] = loader
+}
+
+// AllowExternalPackages allowlists the cuex Package resources declaring the given import paths. The resources are
+// read when the paths are imported for the first time, so that they can be applied after the controller starts
+// without watching the external packages.
+func AllowExternalPackages(paths ...string) {
+	for _, path := range paths {
+		RegisterPackageLoader(path, newExternalPackageLoader(path, singleton.DynamicClient.Get))
+	}
+}
+
+// newExternalPackageLoader returns the loader building the package from the cuex Package resource declaring the path
+func newExternalPackageLoader(path string, getClient func() dynamic.Interface) PackageLoader {
+	return func() (cuexruntime.Package, error) {
+		pkgs, err := getClient().Resource(cuexv1alpha1.PackageGroupVersionResource).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range pkgs.Items {
+			pkg := &cuexv1alpha1.Package{}
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, pkg); err != nil {
+				return nil, err
+			}
+			if pkg.Spec.Path == path {
+				return cuexruntime.NewExternalPackage(pkg)
+			}
+		}
+		return nil, fmt.Errorf("no cuex package declares the import path %s", path)
+	}
+}
+
+// loadImportedPackages registers the allowlisted packages imported by the template which are not loaded yet. The
+// packages are built without holding the lock as the loaders may call the API server.
+func loadImportedPackages(template string) {
+	packagesMu.RLock()
+	noLoader := len(packageLoaders) == 0
+	packagesMu.RUnlock()
+	if noLoader {
+		return
+	}
+	f, err := parseTemplate(template)
+	if err != nil {
+		return
+	}
+	loaders := map[string]PackageLoader{}
+	packagesMu.RLock()
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		loader, allowed := packageLoaders[path]
+		if _, loaded := registeredPackages[path]; loaded || !allowed {
+			continue
+		}
+		loaders[path] = loader
+	}
+	packagesMu.RUnlock()
+
+	for path, loader := range loaders {
+		pkg, err := loader()
+		if err != nil {
+			klog.ErrorS(err, "failed to load cuex package", "path", path)
+			continue
+		}
+		packagesMu.Lock()
+		// the package might be loaded by a concurrent rendering meanwhile
+		if _, loaded := registeredPackages[path]; !loaded {
+			registeredPackages[path] = pkg
+			packageCompilers = map[string]*cuex.Compiler{}
+		}
+		packagesMu.Unlock()
+	}
+}
+
+// GetCompiler returns the compiler for the template. The registered packages required by the template are
+// declared by its imports, if there is none the default compiler is returned.
+func GetCompiler(template string) *cuex.Compiler {
+	loadImportedPackages(template)
+	packagesMu.RLock()
+	paths := getRegisteredImports(template)
+	if len(paths) == 0 {
+		packagesMu.RUnlock()
+		return cuex.DefaultCompiler.Get()
+	}
+	key := strings.Join(paths, ",")
+	compiler, found := packageCompilers[key]
+	packagesMu.RUnlock()
+	if found {
+		return compiler
+	}
+
+	packagesMu.Lock()
+	defer packagesMu.Unlock()
+	if compiler, found = packageCompilers[key]; found {
+		return compiler
+	}
+	var pkgs []cuexruntime.Package
+	for _, path := range paths {
+		pkgs = append(pkgs, registeredPackages[path])
+	}
+	compiler = newCompiler(pkgs...)
+	packageCompilers[key] = compiler
+	return compiler
+}
+
+// newCompiler builds a compiler loading the packages on top of the packages of the default compiler, the given
+// packages take the place of the default ones with the same import paths
+func newCompiler(pkgs ...cuexruntime.Package) *cuex.Compiler {
+	defaultCompiler := cuex.DefaultCompiler.Get()
+	var opts []cuexruntime.PackageManagerOption
+	for _, pkg := range defaultCompiler.Internals.Values() {
+		opts = append(opts, cuexruntime.WithInternalPackage{Package: pkg})
+	}
+	for _, pkg := range pkgs {
+		opts = append(opts, cuexruntime.WithInternalPackage{Package: pkg})
+	}
+	pm := cuexruntime.NewPackageManager(opts...)
+	// share the external packages so that the changes watched by the default compiler are visible
+	pm.Externals = defaultCompiler.Externals
+	return &cuex.Compiler{PackageManager: pm}
+}
+
+// getRegisteredImports returns the sorted import paths of the template which refer to registered packages,
+// must be called with the lock held
+func getRegisteredImports(template string) []string {
+	if len(registeredPackages) == 0 {
+		return nil
+	}
+	f, err := parseTemplate(template)
+	if err != nil {
+		// leave the syntax error to the compiler
+		return nil
+	}
+	var paths []string
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if _, found := registeredPackages[path]; found {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	cuexv1alpha1 "github.com/kubevela/pkg/apis/cue/v1alpha1"
+	"github.com/kubevela/pkg/cue/cuex"
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+type echoVars struct {
+	Input string `json:"input"`
+}
+
+func TestRegisterPackage(t *testing.T) {
+	pkg, err := cuexruntime.NewInternalPackage("org/echo", `
+package echo
+#Upper: {
+	#do:       "upper"
+	#provider: "org/echo"
+	input:   string
+	output?: string
+}
+`, map[string]cuexruntime.ProviderFn{
+		"upper": cuexruntime.GenericProviderFn[echoVars, map[string]string](func(_ context.Context, in *echoVars) (*map[string]string, error) {
+			return &map[string]string{"output": strings.ToUpper(in.Input)}, nil
+		}),
+	})
+	require.NoError(t, err)
+	RegisterPackage(pkg)
+	t.Cleanup(func() { UnregisterPackage(pkg.GetPath()) })
+
+	require.Same(t, cuex.DefaultCompiler.Get(), GetCompiler(`output: {}`))
+	template := `
+import "vela/org/echo"
+
+upper: echo.#Upper & {input: parameter.name}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: upper.output
+}
+`
+	compiler := GetCompiler(template)
+	require.NotSame(t, cuex.DefaultCompiler.Get(), compiler)
+	require.Same(t, compiler, GetCompiler(template))
+	_, found := cuex.DefaultCompiler.Get().Internals.Get("vela/org/echo")
+	require.False(t, found)
+
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	wd := NewWorkloadAbstractEngine("comp")
+	require.NoError(t, wd.Complete(ctx, template, map[string]interface{}{"name": "val"}))
+	base, _ := ctx.Output()
+	s, err := base.String()
+	require.NoError(t, err)
+	require.Contains(t, s, `name: "VAL"`)
+
+	UnregisterPackage(pkg.GetPath())
+	require.Same(t, cuex.DefaultCompiler.Get(), GetCompiler(template))
+}
+
+func TestRegisterPackageLoader(t *testing.T) {
+	loads := 0
+	RegisterPackageLoader("vela/org/echo", func() (cuexruntime.Package, error) {
+		loads++
+		if loads == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return newEchoPackage(t, strings.ToUpper), nil
+	})
+	t.Cleanup(func() {
+		packagesMu.Lock()
+		delete(packageLoaders, "vela/org/echo")
+		packagesMu.Unlock()
+		UnregisterPackage("vela/org/echo")
+	})
+
+	require.Same(t, cuex.DefaultCompiler.Get(), GetCompiler(`output: {}`))
+	require.Equal(t, 0, loads)
+	template := `
+import "vela/org/echo"
+
+echoed: echo.#Echo & {input: parameter.name}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: echoed.output
+}
+`
+	require.Same(t, cuex.DefaultCompiler.Get(), GetCompiler(template))
+	compiler := GetCompiler(template)
+	require.NotSame(t, cuex.DefaultCompiler.Get(), compiler)
+	require.Same(t, compiler, GetCompiler(template))
+	require.Equal(t, 2, loads)
+
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(ctx, template, map[string]interface{}{"name": "val"}))
+	base, _ := ctx.Output()
+	s, err := base.String()
+	require.NoError(t, err)
+	require.Contains(t, s, `name: "VAL"`)
+}
+
+func TestExternalPackageLoader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cuexv1alpha1.AddToScheme(scheme))
+	cli := dynamicfake.NewSimpleDynamicClient(scheme)
+	loader := newExternalPackageLoader("ext/echo", func() dynamic.Interface { return cli })
+	_, err := loader()
+	require.ErrorContains(t, err, "no cuex package declares the import path ext/echo")
+
+	// the package applied after the loader is registered is read on the first import
+	cli = dynamicfake.NewSimpleDynamicClient(scheme, &cuexv1alpha1.Package{
+		TypeMeta:   metav1.TypeMeta{APIVersion: cuexv1alpha1.GroupVersion.String(), Kind: "Package"},
+		ObjectMeta: metav1.ObjectMeta{Name: "echo", Namespace: "vela-system"},
+		Spec: cuexv1alpha1.PackageSpec{
+			Path:      "ext/echo",
+			Templates: map[string]string{"echo.cue": "package echo\n#Echo: {input: string, output: input}"},
+		},
+	})
+	RegisterPackageLoader("ext/echo", loader)
+	t.Cleanup(func() {
+		packagesMu.Lock()
+		delete(packageLoaders, "ext/echo")
+		packagesMu.Unlock()
+		UnregisterPackage("ext/echo")
+	})
+	template := `
+import "ext/echo"
+
+echoed: echo.#Echo & {input: parameter.name}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: echoed.output
+}
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(ctx, template, map[string]interface{}{"name": "val"}))
+	base, _ := ctx.Output()
+	s, err := base.String()
+	require.NoError(t, err)
+	require.Contains(t, s, `name: "val"`)
+}
+
+func TestCELPackage(t *testing.T) {
+	template := `
+import "vela/cel"
+
+check: cel.#Eval & {
+	$params: {
+		expression: "size(name) < 20 ? name : name.substring(0, 20)"
+		variables: name: context.name
+	}
+}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: check.$returns.result
+}
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "a-component-with-a-long-name", Namespace: "default"})
+	wd := NewWorkloadAbstractEngine("comp")
+	require.NoError(t, wd.Complete(ctx, template, nil))
+	base, _ := ctx.Output()
+	s, err := base.String()
+	require.NoError(t, err)
+	require.Contains(t, s, `name: "a-component-with-a-l"`)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/kubevela/pkg/cue/cuex"
+
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+// anyElement is the path segment indexing the elements of the lists and the values of the maps
+const anyElement = "*"
+
+// fieldConstraint is the declaration of a parameter in the template
+type fieldConstraint struct {
+	Type       string
+	Default    string
+	Constraint string
+}
+
+// constraintIndex maps the paths of the parameters declared by a template to their declarations, e.g.
+// parameter.env.*.name for the name of the elements of the env list
+type constraintIndex map[string]fieldConstraint
+
+// buildConstraintIndex compiles the template without the parameters and indexes the declarations of all the
+// parameters, nil is returned if the template does not compile
+func buildConstraintIndex(template string) constraintIndex {
+	val, err := GetCompiler(template).CompileStringWithOptions(context.Background(), renderTemplate(template), cuex.DisableResolveProviderFunctions{})
+	if err != nil {
+		return nil
+	}
+	index := constraintIndex{}
+	index.add(velaprocess.ParameterFieldName, val.LookupPath(cue.ParsePath(velaprocess.ParameterFieldName)))
+	return index
+}
+
+func (index constraintIndex) add(path string, v cue.Value) {
+	if !v.Exists() {
+		return
+	}
+	kind := v.IncompleteKind()
+	if kind == cue.TopKind {
+		return
+	}
+	fc := fieldConstraint{Type: kind.String()}
+	if d, ok := v.Default(); ok && d.IsConcrete() {
+		fc.Default = fmt.Sprint(d)
+	}
+	switch kind {
+	case cue.StructKind:
+		if it, err := v.Fields(cue.Optional(true)); err == nil {
+			for it.Next() {
+				if sel := it.Selector(); sel.IsString() {
+					index.add(path+"."+sel.Unquoted(), it.Value())
+				}
+			}
+		}
+		index.add(path+"."+anyElement, v.LookupPath(cue.MakePath(cue.AnyString)))
+	case cue.ListKind:
+		index.add(path+"."+anyElement, v.LookupPath(cue.MakePath(cue.AnyIndex)))
+	default:
+		fc.Constraint = fmt.Sprint(v)
+	}
+	index[path] = fc
+}
+
+// lookup returns the declaration of the field at the path, the list indexes and the map keys are resolved to the
+// declaration of the elements
+func (index constraintIndex) lookup(path string) (fieldConstraint, bool) {
+	if fc, found := index[path]; found {
+		return fc, true
+	}
+	segments := strings.Split(path, ".")
+	resolved := segments[0]
+	for _, seg := range segments[1:] {
+		switch {
+		case hasKey(index, resolved+"."+seg):
+			resolved += "." + seg
+		case hasKey(index, resolved+"."+anyElement):
+			resolved += "." + anyElement
+		default:
+			return fieldConstraint{}, false
+		}
+	}
+	fc, found := index[resolved]
+	return fc, found
+}
+
+func hasKey(index constraintIndex, path string) bool {
+	_, found := index[path]
+	return found
+}
+
+// attachConstraints completes the field errors with the declarations of the parameters in the template. The
+// template is compiled once for all the errors, the details parsed from the CUE messages take precedence.
+func (e *CueValidationError) attachConstraints(template string) {
+	if template == "" || len(e.ParameterErrors) == 0 {
+		return
+	}
+	index := buildConstraintIndex(template)
+	if len(index) == 0 {
+		return
+	}
+	var attach func(errs []FieldError)
+	attach = func(errs []FieldError) {
+		for i := range errs {
+			attach(errs[i].Disjuncts)
+			fc, found := index.lookup(errs[i].Path)
+			if !found {
+				continue
+			}
+			if errs[i].ExpectedType == "" {
+				errs[i].ExpectedType = fc.Type
+			}
+			if errs[i].Constraint == "" {
+				errs[i].Constraint = fc.Constraint
+			}
+			errs[i].Default = fc.Default
+		}
+	}
+	attach(e.ParameterErrors)
+}
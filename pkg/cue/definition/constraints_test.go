@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+func TestConstraintIndex(t *testing.T) {
+	index := buildConstraintIndex(`
+parameter: {
+	replicas: *1 | int
+	env?: [...{
+		name:   string
+		value?: string
+	}]
+	resources: limits: cpu: *"500m" | string
+	labels?: [string]: string
+}
+output: metadata: name: context.name
+`)
+	fc, found := index.lookup("parameter.replicas")
+	require.True(t, found)
+	require.Equal(t, fieldConstraint{Type: "int", Default: "1", Constraint: "*1 | int"}, fc)
+
+	fc, found = index.lookup("parameter.env.2.name")
+	require.True(t, found)
+	require.Equal(t, "string", fc.Type)
+
+	fc, found = index.lookup("parameter.resources.limits.cpu")
+	require.True(t, found)
+	require.Equal(t, `"500m"`, fc.Default)
+
+	fc, found = index.lookup("parameter.labels.app")
+	require.True(t, found)
+	require.Equal(t, "string", fc.Type)
+
+	_, found = index.lookup("parameter.env.2.unknown")
+	require.False(t, found)
+	require.Nil(t, buildConstraintIndex(`parameter: {`))
+}
+
+func TestAttachConstraints(t *testing.T) {
+	template := `
+parameter: {
+	ports: [...{
+		port:     int & >=1 & <=65535
+		protocol: *"TCP" | "UDP"
+	}]
+}
+output: {
+	apiVersion: "v1"
+	kind:       "Service"
+	spec: ports: parameter.ports
+}
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+	err := NewWorkloadAbstractEngine("web").Complete(ctx, template, map[string]interface{}{
+		"ports": []interface{}{map[string]interface{}{"port": 0}},
+	})
+	var verr *CueValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.ParameterErrors, 1)
+	fieldErr := verr.ParameterErrors[0]
+	require.Equal(t, "parameter.ports.0.port", fieldErr.Path)
+	require.Equal(t, ErrorCodeOutOfBound, fieldErr.Code)
+	require.Equal(t, "int", fieldErr.ExpectedType)
+	require.Equal(t, ">=1", fieldErr.Constraint)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/token"
+	"github.com/kubevela/pkg/util/slices"
+	"github.com/kubevela/workflow/pkg/cue/process"
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/oam-dev/kubevela/pkg/features"
+)
+
+// TraitRenderWorkers the max number of traits of a component compiled concurrently
+var TraitRenderWorkers = 4
+
+// contextOutputFields the context fields holding the rendered workload and auxiliaries
+var contextOutputFields = []string{"output", "outputs"}
+
+// TraitRender is a trait to be rendered into the process context
+type TraitRender struct {
+	Name     string
+	Engine   AbstractEngine
+	Template string
+	Params   interface{}
+}
+
+type compiledTrait struct {
+	val cue.Value
+	key validationErrorCacheKey
+	err error
+}
+
+// CompleteTraits renders the traits in the given order. When the EnableParallelTraitRendering feature is enabled,
+// the independent traits, i.e. the traits declaring no schedule and not referring to context.output(s), are
+// compiled concurrently beforehand since their compilation doesn't depend on the traits rendered before them.
+// Validation, outputs and patches are always applied in the given order, so the result is deterministic.
+func CompleteTraits(ctx process.Context, traits []TraitRender) error {
+	return completeTraits(ctx, traits, false)
+}
+
+// AggregateCompleteTraits renders the traits like CompleteTraits, but keeps rendering the remaining traits after a
+// trait fails the validation. The validation errors of all the traits are returned as ValidationErrors, the other
+// errors still stop the rendering.
+func AggregateCompleteTraits(ctx process.Context, traits []TraitRender) error {
+	return completeTraits(ctx, traits, true)
+}
+
+func completeTraits(ctx process.Context, traits []TraitRender, aggregate bool) error {
+	var validationErrs ValidationErrors
+	var compiled []*compiledTrait
+	if feature.DefaultMutableFeatureGate.Enabled(features.EnableParallelTraitRendering) {
+		var err error
+		if compiled, err = precompileTraits(ctx, traits); err != nil {
+			return err
+		}
+	}
+	for i, tr := range traits {
+		var err error
+		if td, ok := tr.Engine.(*traitDef); ok && len(compiled) > 0 && compiled[i] != nil {
+			if err = compiled[i].err; err == nil {
+				err = td.decorateError(td.apply(ctx, compiled[i].val, tr.Template, compiled[i].key), tr.Template)
+			}
+		} else {
+			err = tr.Engine.Complete(ctx, tr.Template, tr.Params)
+		}
+		var verr *CueValidationError
+		if err != nil && aggregate && errors.As(err, &verr) {
+			validationErrs = append(validationErrs, verr)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "evaluate template trait=%s", tr.Name)
+		}
+	}
+	if len(validationErrs) > 0 {
+		return validationErrs
+	}
+	return nil
+}
+
+// precompileTraits compiles the independent traits concurrently with a bounded worker pool, the result is nil for
+// the traits to be compiled in order
+func precompileTraits(ctx process.Context, traits []TraitRender) ([]*compiledTrait, error) {
+	var independent []int
+	for i, tr := range traits {
+		if _, ok := tr.Engine.(*traitDef); ok && isIndependentTrait(tr.Template) {
+			independent = append(independent, i)
+		}
+	}
+	if len(independent) < 2 {
+		return nil, nil
+	}
+	// the base context is read once as the process context must not be accessed concurrently
+	c, err := (&traitDef{}).contextFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	namespace := contextNamespace(ctx)
+	results := slices.ParMap(independent, func(i int) *compiledTrait {
+		val, key, err := traits[i].Engine.(*traitDef).compile(ctx.GetCtx(), namespace, traits[i].Template, traits[i].Params, c)
+		return &compiledTrait{val: val, key: key, err: err}
+	}, slices.Parallelism(TraitRenderWorkers))
+	compiled := make([]*compiledTrait, len(traits))
+	for j, i := range independent {
+		compiled[i] = results[j]
+	}
+	return compiled, nil
+}
+
+// isIndependentTrait checks whether the compilation of the trait template is unaffected by the traits rendered
+// before it
+func isIndependentTrait(template string) bool {
+	schedule, err := GetTraitSchedule(template)
+	if err != nil || schedule.Stage != 1 || len(schedule.DependsOn) > 0 {
+		return false
+	}
+	return !RefersContextFields(template, contextOutputFields...)
+}
+
+// RefersContextFields checks whether the template refers to any of the given fields of the context. The uses of
+// the context other than selecting one of its fields, e.g. aliasing or embedding it, are treated as referring them
+// as well since what they read can't be told from the syntax, and so is a template failing to parse.
+func RefersContextFields(template string, fields ...string) bool {
+	f, err := parseTemplate(template)
+	if err != nil {
+		return true
+	}
+	return refersContextFields(f, fields)
+}
+
+func refersContextFields(f *ast.File, fields []string) bool {
+	found := false
+	var before func(ast.Node) bool
+	before = func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.Field:
+			// plain labels declare fields instead of referring them
+			switch x.Label.(type) {
+			case *ast.Ident, *ast.BasicLit:
+			default:
+				ast.Walk(x.Label, before, nil)
+			}
+			if x.Value != nil {
+				ast.Walk(x.Value, before, nil)
+			}
+			return false
+		case *ast.SelectorExpr:
+			if isContextIdent(x.X) {
+				name, _, err := ast.LabelName(x.Sel)
+				found = err != nil || slices.Contains(fields, name)
+			} else {
+				ast.Walk(x.X, before, nil)
+			}
+			return false
+		case *ast.IndexExpr:
+			if !isContextIdent(x.X) {
+				return true
+			}
+			lit, ok := x.Index.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				found = true
+				return false
+			}
+			name, err := literal.Unquote(lit.Value)
+			found = err != nil || slices.Contains(fields, name)
+			return false
+		case *ast.Ident:
+			found = isContextIdent(x)
+		}
+		return true
+	}
+	ast.Walk(f, before, nil)
+	return found
+}
+
+func isContextIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "context"
+}
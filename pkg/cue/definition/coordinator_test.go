@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/features"
+)
+
+func TestIsIndependentTrait(t *testing.T) {
+	require.True(t, isIndependentTrait(`patch: metadata: labels: app: context.name`))
+	require.False(t, isIndependentTrait(`patch: metadata: labels: app: context.output.metadata.name`))
+	require.False(t, isIndependentTrait(`outputs: svc: spec: selector: context.outputs.web.metadata.labels`))
+	require.False(t, isIndependentTrait(`outputs: svc: spec: selector: context["output"].metadata.labels`))
+	require.True(t, isIndependentTrait(`patch: metadata: labels: app: context["name"]`))
+	require.True(t, isIndependentTrait(`patch: metadata: annotations: owner: parameter.context.outputs`))
+	require.False(t, isIndependentTrait(`let c = context
+patch: metadata: labels: app: c.output.metadata.name`))
+	require.False(t, isIndependentTrait(`_ctx: context
+patch: metadata: labels: app: _ctx.outputs.web.metadata.name`))
+	require.False(t, isIndependentTrait(`patch: metadata: labels: { context }`))
+	require.False(t, isIndependentTrait(`patch: metadata: labels: app: "\(context.output.metadata.name)"`))
+	require.False(t, isIndependentTrait(`patch: metadata: labels: app: context[parameter.key]`))
+	require.False(t, isIndependentTrait(`#schedule: stage: "post-workload"
+patch: metadata: labels: app: context.name`))
+	require.False(t, isIndependentTrait(`#schedule: dependsOn: ["labels"]
+patch: metadata: labels: app: context.name`))
+}
+
+func TestCompleteTraits(t *testing.T) {
+	workload := `
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: context.name
+	spec: replicas: 1
+}
+`
+	var traits []TraitRender
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("label-%d", i)
+		traits = append(traits, TraitRender{
+			Name:     name,
+			Engine:   NewTraitAbstractEngine(name),
+			Template: fmt.Sprintf(`patch: metadata: labels: "%s": parameter.value`, name),
+			Params:   map[string]interface{}{"value": name},
+		})
+	}
+	traits = append(traits,
+		TraitRender{
+			Name:   "service",
+			Engine: NewTraitAbstractEngine("service"),
+			Template: `outputs: service: {
+	apiVersion: "v1"
+	kind:       "Service"
+	spec: selector: context.output.metadata.labels
+}`,
+		},
+		TraitRender{
+			Name:     "annotation",
+			Engine:   NewTraitAbstractEngine("annotation"),
+			Template: `patchOutputs: service: metadata: annotations: owner: context.name`,
+		},
+	)
+
+	render := func(enabled bool) string {
+		featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.EnableParallelTraitRendering, enabled)
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+		require.NoError(t, NewWorkloadAbstractEngine("web").Complete(ctx, workload, nil))
+		require.NoError(t, CompleteTraits(ctx, traits))
+		base, auxiliaries := ctx.Output()
+		s, err := base.String()
+		require.NoError(t, err)
+		for _, aux := range auxiliaries {
+			as, err := aux.Ins.String()
+			require.NoError(t, err)
+			s += "---\n" + as
+		}
+		return s
+	}
+	serial := render(false)
+	for i := 0; i < 6; i++ {
+		require.Contains(t, serial, fmt.Sprintf(`"label-%d": "label-%d"`, i, i))
+	}
+	require.Contains(t, serial, `owner: "web"`)
+	require.Equal(t, serial, render(true))
+
+	traits = append(traits, TraitRender{
+		Name:     "broken",
+		Engine:   NewTraitAbstractEngine("broken"),
+		Template: `patch: metadata: labels: broken: parameter.value & 1`,
+		Params:   map[string]interface{}{"value": "a"},
+	})
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.EnableParallelTraitRendering, true)
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("web").Complete(ctx, workload, nil))
+	require.ErrorContains(t, CompleteTraits(ctx, traits), "evaluate template trait=broken")
+}
+
+func TestAggregateCompleteTraits(t *testing.T) {
+	newTrait := func(name string, value interface{}) TraitRender {
+		return TraitRender{
+			Name:     name,
+			Engine:   NewTraitAbstractEngine(name),
+			Template: fmt.Sprintf("parameter: value: string\npatch: metadata: labels: %q: parameter.value", name),
+			Params:   map[string]interface{}{"value": value},
+		}
+	}
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("web").Complete(ctx, `output: {apiVersion: "v1", kind: "ConfigMap"}`, nil))
+	traits := []TraitRender{newTrait("a", 1), newTrait("b", "b"), newTrait("c", 2)}
+	err := AggregateCompleteTraits(ctx, traits)
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	require.Equal(t, "a", errs[0].EntityName)
+	require.Equal(t, "c", errs[1].EntityName)
+
+	base, _ := ctx.Output()
+	s, sErr := base.String()
+	require.NoError(t, sErr)
+	require.Contains(t, s, `b: "b"`)
+
+	report := &AggregateValidationError{}
+	require.True(t, report.Add("web", err))
+	require.True(t, report.Add("web", errs[0]))
+	require.False(t, report.Add("web", fmt.Errorf("not a validation error")))
+	require.Len(t, report.Components, 1)
+	require.Len(t, report.Components[0].Errors, 3)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue/ast"
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/registry"
+)
+
+// ParameterDefaultsFieldName is the name of the field declaring the config which provides the defaults of the
+// unspecified parameters, i.e. parameterDefaults: {config: "image-registry", namespace: "vela-system"}
+const ParameterDefaultsFieldName = "parameterDefaults"
+
+// ConfigReader reads the properties of a config, it is implemented by the config factory and registered in the
+// provider registry as pkg/config depends on this package.
+type ConfigReader interface {
+	ReadConfig(ctx context.Context, namespace, name string) (map[string]interface{}, error)
+}
+
+// ParameterDefaultsCacheTTL how long the properties of a config read by the cached config reader are reused
+var ParameterDefaultsCacheTTL = 30 * time.Second
+
+type cachedConfig struct {
+	properties []byte
+	expireAt   time.Time
+}
+
+// cachedConfigReader caches the properties read by the underlying reader per config until they expire, so that the
+// config is not read on every rendering of the definitions declaring it. Errors are not cached.
+type cachedConfigReader struct {
+	reader  ConfigReader
+	mu      sync.Mutex
+	configs map[string]*cachedConfig
+}
+
+// NewCachedConfigReader wraps the reader with a cache of the properties of each config, the cached properties are
+// dropped after ParameterDefaultsCacheTTL
+func NewCachedConfigReader(reader ConfigReader) ConfigReader {
+	return &cachedConfigReader{reader: reader, configs: map[string]*cachedConfig{}}
+}
+
+// ReadConfig implements ConfigReader, each call returns a copy of the properties which can be modified by the caller
+func (r *cachedConfigReader) ReadConfig(ctx context.Context, namespace, name string) (map[string]interface{}, error) {
+	key := namespace + "/" + name
+	r.mu.Lock()
+	config, found := r.configs[key]
+	if found && time.Now().After(config.expireAt) {
+		delete(r.configs, key)
+		found = false
+	}
+	r.mu.Unlock()
+	if !found {
+		properties, err := r.reader.ReadConfig(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		bt, err := json.Marshal(properties)
+		if err != nil {
+			return nil, err
+		}
+		config = &cachedConfig{properties: bt, expireAt: time.Now().Add(ParameterDefaultsCacheTTL)}
+		r.mu.Lock()
+		r.configs[key] = config
+		r.mu.Unlock()
+	}
+	properties := map[string]interface{}{}
+	if err := json.Unmarshal(config.properties, &properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// ParameterDefaultsRef refers to the config providing the parameter defaults of a definition
+type ParameterDefaultsRef struct {
+	Config    string
+	Namespace string
+}
+
+// GetParameterDefaultsRef parses the `parameterDefaults` field of the template. Only literal values are honored,
+// the namespace defaults to the KubeVela system namespace.
+func GetParameterDefaultsRef(template string) *ParameterDefaultsRef {
+	f, err := parseTemplate(template)
+	if err != nil {
+		// leave the syntax error to the rendering
+		return nil
+	}
+	for _, decl := range f.Decls {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if label, _, err := ast.LabelName(field.Label); err != nil || label != ParameterDefaultsFieldName {
+			continue
+		}
+		st, ok := field.Value.(*ast.StructLit)
+		if !ok {
+			return nil
+		}
+		ref := &ParameterDefaultsRef{Namespace: oam.SystemDefinitionNamespace}
+		for _, elt := range st.Elts {
+			f, ok := elt.(*ast.Field)
+			if !ok {
+				continue
+			}
+			label, _, err := ast.LabelName(f.Label)
+			if err != nil {
+				continue
+			}
+			s, ok := stringLit(f.Value)
+			if !ok || s == "" {
+				continue
+			}
+			switch label {
+			case "config":
+				ref.Config = s
+			case "namespace":
+				ref.Namespace = s
+			}
+		}
+		if ref.Config == "" {
+			return nil
+		}
+		return ref
+	}
+	return nil
+}
+
+// ApplyParameterDefaults fills the parameters unspecified by the user with the properties of the config declared
+// by the template. The config must be in the KubeVela system namespace or the namespace of the application, so that
+// a definition can't read the configs of other tenants. The parameters are returned as is if the template declares
+// no config or no config reader is registered, e.g. in offline rendering. The mock configs of the context take
+// precedence over the registered reader.
+func ApplyParameterDefaults(ctx context.Context, namespace, template string, params interface{}) (interface{}, error) {
+	ref := GetParameterDefaultsRef(template)
+	if ref == nil {
+		return params, nil
+	}
+	if ref.Namespace != oam.SystemDefinitionNamespace && ref.Namespace != namespace {
+		return nil, errors.Errorf("the config %s/%s providing the parameter defaults must be in the namespace %s or %s",
+			ref.Namespace, ref.Config, oam.SystemDefinitionNamespace, namespace)
+	}
+	reader, ok := getMockConfigReader(ctx)
+	if !ok {
+		reader, ok = registry.Get[ConfigReader]()
+	}
+	if !ok {
+		return params, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	defaults, err := reader.ReadConfig(ctx, ref.Namespace, ref.Config)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "read parameter defaults from config %s/%s", ref.Namespace, ref.Config)
+	}
+	userParams := map[string]interface{}{}
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		if string(bt) != "null" {
+			if err := json.Unmarshal(bt, &userParams); err != nil {
+				return nil, errors.Wrap(err, "parameter must be an object to be defaulted")
+			}
+		}
+	}
+	return mergeParameterDefaults(userParams, defaults), nil
+}
+
+// mergeParameterDefaults sets the keys of defaults missing in params, nested objects are merged recursively
+func mergeParameterDefaults(params, defaults map[string]interface{}) map[string]interface{} {
+	for k, d := range defaults {
+		v, found := params[k]
+		if !found {
+			params[k] = d
+			continue
+		}
+		vm, ok1 := v.(map[string]interface{})
+		dm, ok2 := d.(map[string]interface{})
+		if ok1 && ok2 {
+			params[k] = mergeParameterDefaults(vm, dm)
+		}
+	}
+	return params
+}
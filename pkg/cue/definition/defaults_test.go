@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/registry"
+)
+
+type fakeConfigReader map[string]map[string]interface{}
+
+func (r fakeConfigReader) ReadConfig(_ context.Context, namespace, name string) (map[string]interface{}, error) {
+	config, found := r[namespace+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("config %s/%s not found", namespace, name)
+	}
+	return config, nil
+}
+
+func TestGetParameterDefaultsRef(t *testing.T) {
+	require.Nil(t, GetParameterDefaultsRef(`parameter: image: string`))
+	require.Nil(t, GetParameterDefaultsRef(`parameterDefaults: config: parameter.name`))
+	require.Equal(t, &ParameterDefaultsRef{Config: "defaults", Namespace: "vela-system"},
+		GetParameterDefaultsRef(`parameterDefaults: config: "defaults"`))
+	require.Equal(t, &ParameterDefaultsRef{Config: "defaults", Namespace: "team"},
+		GetParameterDefaultsRef(`parameterDefaults: {config: "defaults", namespace: "team"}`))
+}
+
+func TestParameterDefaults(t *testing.T) {
+	snapshot := registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(snapshot) })
+
+	template := `
+parameterDefaults: config: "defaults"
+parameter: {
+	image:    string
+	registry: string
+	resources: {cpu: string, memory: string}
+}
+output: {
+	apiVersion: "v1"
+	kind:       "Pod"
+	spec: containers: [{
+		image: parameter.registry + "/" + parameter.image
+		resources: limits: parameter.resources
+	}]
+}
+`
+	params := map[string]interface{}{"image": "nginx", "resources": map[string]interface{}{"cpu": "1"}}
+	render := func() (string, error) {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+		if err := NewWorkloadAbstractEngine("comp").Complete(ctx, template, params); err != nil {
+			return "", err
+		}
+		base, _ := ctx.Output()
+		return base.String()
+	}
+
+	// no config reader registered, e.g. offline rendering
+	registry.Restore(registry.RegistrySnapshot{})
+	s, err := render()
+	require.NoError(t, err)
+	require.NotContains(t, s, "docker.io")
+
+	registry.RegisterAs[ConfigReader](fakeConfigReader{
+		"vela-system/defaults": {"image": "busybox", "registry": "docker.io", "resources": map[string]interface{}{"cpu": "2", "memory": "1Gi"}},
+	})
+	s, err = render()
+	require.NoError(t, err)
+	require.Contains(t, s, `image: "docker.io/nginx"`)
+	require.Contains(t, s, `cpu:    "1"`)
+	require.Contains(t, s, `memory: "1Gi"`)
+
+	registry.RegisterAs[ConfigReader](fakeConfigReader{})
+	_, err = render()
+	require.ErrorContains(t, err, "read parameter defaults from config vela-system/defaults")
+}
+
+func TestParameterDefaultsNamespace(t *testing.T) {
+	snapshot := registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(snapshot) })
+	registry.RegisterAs[ConfigReader](fakeConfigReader{
+		"default/defaults": {"registry": "docker.io"},
+		"team/defaults":    {"registry": "ghcr.io"},
+	})
+
+	template := `
+parameterDefaults: {config: "defaults", namespace: "default"}
+parameter: registry: string
+`
+	params, err := ApplyParameterDefaults(context.Background(), "default", template, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"registry": "docker.io"}, params)
+
+	// the configs of the other namespaces are not readable
+	_, err = ApplyParameterDefaults(context.Background(), "team", template, nil)
+	require.ErrorContains(t, err, "the config default/defaults providing the parameter defaults must be in the namespace vela-system or team")
+}
+
+type countingConfigReader struct {
+	fakeConfigReader
+	reads int
+}
+
+func (r *countingConfigReader) ReadConfig(ctx context.Context, namespace, name string) (map[string]interface{}, error) {
+	r.reads++
+	return r.fakeConfigReader.ReadConfig(ctx, namespace, name)
+}
+
+func TestCachedConfigReader(t *testing.T) {
+	ttl := ParameterDefaultsCacheTTL
+	t.Cleanup(func() { ParameterDefaultsCacheTTL = ttl })
+	ParameterDefaultsCacheTTL = time.Minute
+
+	underlying := &countingConfigReader{fakeConfigReader: fakeConfigReader{
+		"vela-system/defaults": {"registry": "docker.io"},
+	}}
+	reader := NewCachedConfigReader(underlying)
+
+	config, err := reader.ReadConfig(context.Background(), "vela-system", "defaults")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"registry": "docker.io"}, config)
+	// the returned properties are copies
+	config["registry"] = "ghcr.io"
+	config, err = reader.ReadConfig(context.Background(), "vela-system", "defaults")
+	require.NoError(t, err)
+	require.Equal(t, "docker.io", config["registry"])
+	require.Equal(t, 1, underlying.reads)
+
+	// the errors are not cached
+	_, err = reader.ReadConfig(context.Background(), "vela-system", "missing")
+	require.ErrorContains(t, err, "config vela-system/missing not found")
+	_, err = reader.ReadConfig(context.Background(), "vela-system", "missing")
+	require.Error(t, err)
+	require.Equal(t, 3, underlying.reads)
+
+	// the expired properties are read again
+	ParameterDefaultsCacheTTL = -time.Second
+	reader = NewCachedConfigReader(underlying)
+	_, err = reader.ReadConfig(context.Background(), "vela-system", "defaults")
+	require.NoError(t, err)
+	underlying.fakeConfigReader["vela-system/defaults"] = map[string]interface{}{"registry": "quay.io"}
+	config, err = reader.ReadConfig(context.Background(), "vela-system", "defaults")
+	require.NoError(t, err)
+	require.Equal(t, "quay.io", config["registry"])
+	require.Equal(t, 5, underlying.reads)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/kubevela/workflow/pkg/cue/process"
+	"github.com/pkg/errors"
+)
+
+// DiffOperation is the kind of change of a field between two renderings
+type DiffOperation string
+
+const (
+	// DiffAdd means the field only exists in the next rendering
+	DiffAdd DiffOperation = "add"
+	// DiffRemove means the field only exists in the previous rendering
+	DiffRemove DiffOperation = "remove"
+	// DiffChange means the field exists in both renderings with different values
+	DiffChange DiffOperation = "change"
+)
+
+// OutputFieldDiff is the change of a field of a rendered output
+type OutputFieldDiff struct {
+	// Output is the rendered object owning the field, output for the main workload, outputs.<name> for the
+	// auxiliaries and <trait>.outputs.<name> for the auxiliaries of the traits
+	Output    string        `json:"output"`
+	Path      string        `json:"path"`
+	Operation DiffOperation `json:"operation"`
+	Prev      interface{}   `json:"prev,omitempty"`
+	Next      interface{}   `json:"next,omitempty"`
+}
+
+// String returns the change in the form of <output>:<path> <operation> <prev> -> <next>
+func (d OutputFieldDiff) String() string {
+	switch d.Operation {
+	case DiffAdd:
+		return fmt.Sprintf("%s:%s added %v", d.Output, d.Path, d.Next)
+	case DiffRemove:
+		return fmt.Sprintf("%s:%s removed %v", d.Output, d.Path, d.Prev)
+	default:
+		return fmt.Sprintf("%s:%s changed %v -> %v", d.Output, d.Path, d.Prev, d.Next)
+	}
+}
+
+// DiffOutputs compares the output and outputs rendered in two process contexts field by field, e.g. the renderings
+// of two revisions of an application. The changes are sorted by output and path, an empty result means the
+// renderings are identical and the apply can be skipped. A nil context is regarded as a rendering without outputs.
+func DiffOutputs(prev, next process.Context) ([]OutputFieldDiff, error) {
+	prevObjs, err := renderedObjects(prev)
+	if err != nil {
+		return nil, errors.WithMessage(err, "previous rendering")
+	}
+	nextObjs, err := renderedObjects(next)
+	if err != nil {
+		return nil, errors.WithMessage(err, "next rendering")
+	}
+	var diffs []OutputFieldDiff
+	for name, prevObj := range prevObjs {
+		diffs = diffValue(diffs, name, "", prevObj, nextObjs[name])
+	}
+	for name, nextObj := range nextObjs {
+		if _, found := prevObjs[name]; !found {
+			diffs = diffValue(diffs, name, "", nil, nextObj)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Output != diffs[j].Output {
+			return diffs[i].Output < diffs[j].Output
+		}
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs, nil
+}
+
+// renderedObjects returns the objects rendered in the context keyed by their output names
+func renderedObjects(ctx process.Context) (map[string]interface{}, error) {
+	objs := map[string]interface{}{}
+	if ctx == nil {
+		return objs, nil
+	}
+	base, auxiliaries := ctx.Output()
+	if base != nil {
+		obj, err := base.Unstructured()
+		if err != nil {
+			return nil, errors.WithMessage(err, "output")
+		}
+		objs["output"] = obj.Object
+	}
+	for _, aux := range auxiliaries {
+		name := auxiliaryOutputName(aux)
+		obj, err := aux.Ins.Unstructured()
+		if err != nil {
+			return nil, errors.WithMessage(err, name)
+		}
+		objs[name] = obj.Object
+	}
+	return objs, nil
+}
+
+func diffValue(diffs []OutputFieldDiff, output, path string, prev, next interface{}) []OutputFieldDiff {
+	switch {
+	case prev == nil && next == nil:
+		return diffs
+	case prev == nil:
+		return append(diffs, OutputFieldDiff{Output: output, Path: path, Operation: DiffAdd, Next: next})
+	case next == nil:
+		return append(diffs, OutputFieldDiff{Output: output, Path: path, Operation: DiffRemove, Prev: prev})
+	}
+	prevMap, prevIsMap := prev.(map[string]interface{})
+	nextMap, nextIsMap := next.(map[string]interface{})
+	if prevIsMap && nextIsMap {
+		for k, v := range prevMap {
+			diffs = diffValue(diffs, output, joinDiffPath(path, k), v, nextMap[k])
+		}
+		for k, v := range nextMap {
+			if _, found := prevMap[k]; !found {
+				diffs = diffValue(diffs, output, joinDiffPath(path, k), nil, v)
+			}
+		}
+		return diffs
+	}
+	prevList, prevIsList := prev.([]interface{})
+	nextList, nextIsList := next.([]interface{})
+	if prevIsList && nextIsList {
+		for i := 0; i < len(prevList) || i < len(nextList); i++ {
+			var p, n interface{}
+			if i < len(prevList) {
+				p = prevList[i]
+			}
+			if i < len(nextList) {
+				n = nextList[i]
+			}
+			diffs = diffValue(diffs, output, path+"["+strconv.Itoa(i)+"]", p, n)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(prev, next) {
+		diffs = append(diffs, OutputFieldDiff{Output: output, Path: path, Operation: DiffChange, Prev: prev, Next: next})
+	}
+	return diffs
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
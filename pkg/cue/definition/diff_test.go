@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"testing"
+
+	wfprocess "github.com/kubevela/workflow/pkg/cue/process"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+func TestDiffOutputs(t *testing.T) {
+	template := `
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	spec: {
+		replicas: parameter.replicas
+		template: spec: containers: [{image: parameter.image}]
+	}
+}
+if parameter.expose {
+	outputs: service: {
+		apiVersion: "v1"
+		kind:       "Service"
+		spec: ports: [{port: 80}]
+	}
+}
+parameter: {
+	image:    string
+	replicas: int
+	expose:   bool
+}
+`
+	render := func(params map[string]interface{}) wfprocess.Context {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+		require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(ctx, template, params))
+		return ctx
+	}
+	prev := render(map[string]interface{}{"image": "nginx:1.0", "replicas": 1, "expose": false})
+
+	diffs, err := DiffOutputs(prev, render(map[string]interface{}{"image": "nginx:1.0", "replicas": 1, "expose": false}))
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	diffs, err = DiffOutputs(prev, render(map[string]interface{}{"image": "nginx:2.0", "replicas": 1, "expose": true}))
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+	require.Equal(t, OutputFieldDiff{Output: "output", Path: "spec.template.spec.containers[0].image",
+		Operation: DiffChange, Prev: "nginx:1.0", Next: "nginx:2.0"}, diffs[0])
+	require.Equal(t, "outputs.service", diffs[1].Output)
+	require.Equal(t, DiffAdd, diffs[1].Operation)
+	require.Equal(t, "output:spec.template.spec.containers[0].image changed nginx:1.0 -> nginx:2.0", diffs[0].String())
+
+	diffs, err = DiffOutputs(prev, nil)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, DiffRemove, diffs[0].Operation)
+	require.Equal(t, "output", diffs[0].Output)
+}
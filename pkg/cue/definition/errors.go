@@ -0,0 +1,738 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"github.com/kubevela/workflow/pkg/cue/process"
+	"k8s.io/klog/v2"
+
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/registry"
+)
+
+// ErrorCode is the stable, machine-readable code of a FieldError
+type ErrorCode string
+
+const (
+	// ErrorCodeConflictingValues the provided value conflicts with the value or type declared in the template
+	ErrorCodeConflictingValues ErrorCode = "ConflictingValues"
+	// ErrorCodeOutOfBound the provided value violates a bound constraint like >=1
+	ErrorCodeOutOfBound ErrorCode = "OutOfBound"
+	// ErrorCodeIncompleteValue the field is required but no concrete value is provided
+	ErrorCodeIncompleteValue ErrorCode = "IncompleteValue"
+	// ErrorCodeEmptyDisjunction the provided value matches none of the allowed values
+	ErrorCodeEmptyDisjunction ErrorCode = "EmptyDisjunction"
+	// ErrorCodeFieldNotAllowed the field is not declared in a closed struct
+	ErrorCodeFieldNotAllowed ErrorCode = "FieldNotAllowed"
+	// ErrorCodeInvalidValue any other CUE validation failure
+	ErrorCodeInvalidValue ErrorCode = "InvalidValue"
+)
+
+const (
+	// SeverityError the entry of errs fails the rendering, it is the default severity
+	SeverityError = "error"
+	// SeverityWarning the entry of errs is reported without failing the rendering
+	SeverityWarning = "warning"
+)
+
+// ErrorVerbosity is the verbosity level of the message of CueValidationError
+type ErrorVerbosity string
+
+const (
+	// ErrorVerbosityCompact formats all the errors in one line, suitable for controller logs
+	ErrorVerbosityCompact ErrorVerbosity = "compact"
+	// ErrorVerbosityStandard formats the errors grouped by their source, one line per field
+	ErrorVerbosityStandard ErrorVerbosity = "standard"
+	// ErrorVerbosityVerbose formats the errors grouped by their source with the constraint, the provided value and
+	// the expected type of each field
+	ErrorVerbosityVerbose ErrorVerbosity = "verbose"
+
+	// ErrorVerbosityEnv is the environment variable setting the default verbosity
+	ErrorVerbosityEnv = "VELA_CUE_ERROR_VERBOSITY"
+)
+
+// DefaultErrorVerbosity is the verbosity of the errors returned by the engines created without WithErrorVerbosity
+var DefaultErrorVerbosity = parseErrorVerbosity(os.Getenv(ErrorVerbosityEnv))
+
+func parseErrorVerbosity(s string) ErrorVerbosity {
+	switch v := ErrorVerbosity(s); v {
+	case ErrorVerbosityCompact, ErrorVerbosityVerbose:
+		return v
+	default:
+		return ErrorVerbosityStandard
+	}
+}
+
+// decorateError sets the verbosity of the CueValidationError wrapped in err, attaches the source snippets of the
+// template to its field errors and captures the diagnostics if enabled
+func (d def) decorateError(err error, template string) error {
+	var verr *CueValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	if d.verbosity != "" {
+		verr.Verbosity = d.verbosity
+	}
+	verr.decorate(template)
+	if d.diagnostics {
+		verr.Diagnostics = verr.diagnostics()
+	}
+	return err
+}
+
+var (
+	conflictingValuesRegex = regexp.MustCompile(`^conflicting values (.+?) and (.+?)(?: \(mismatched types (\S+) and (\S+)\))?$`)
+	outOfBoundRegex        = regexp.MustCompile(`^invalid value (.+) \(out of bound (.+)\)$`)
+	incompleteValueRegex   = regexp.MustCompile(`^incomplete value (.+)$`)
+	emptyDisjunctionRegex  = regexp.MustCompile(`^\d+ errors in empty disjunction:?$`)
+)
+
+// FieldError is a single structured CUE validation failure
+type FieldError struct {
+	Code         ErrorCode `json:"code"`
+	Path         string    `json:"path,omitempty"`
+	Message      string    `json:"message"`
+	Constraint   string    `json:"constraint,omitempty"`
+	Provided     string    `json:"provided,omitempty"`
+	ExpectedType string    `json:"expectedType,omitempty"`
+	// Default is the default value of the parameter declared in the template
+	Default string `json:"default,omitempty"`
+	// Line and Column locate the offending expression in the template, starting from 1
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+	// Disjuncts are the failures of the branches of an empty disjunction, Closest marks the branch the provided
+	// value is closest to satisfying if there is a single one
+	Disjuncts []FieldError `json:"disjuncts,omitempty"`
+	Closest   bool         `json:"closest,omitempty"`
+
+	positions []token.Pos
+}
+
+// CueValidationError is returned when the rendering of a definition fails the CUE validation. Error() keeps the
+// human-readable grouped format while the fields allow controllers, webhooks and UIs to consume the failures.
+type CueValidationError struct {
+	Prefix          string         `json:"-"`
+	Verbosity       ErrorVerbosity `json:"-"`
+	EntityType      string         `json:"entityType"`
+	EntityName      string         `json:"entityName"`
+	UserErrors      []string       `json:"userErrors,omitempty"`
+	ParameterErrors []FieldError   `json:"parameterErrors,omitempty"`
+	TemplateErrors  []FieldError   `json:"templateErrors,omitempty"`
+	// Diagnostics the information extracted from the CUE errors, captured when the engine enables WithDiagnostics
+	Diagnostics []string `json:"diagnostics,omitempty"`
+
+	// decorated the source and constraints of the template are attached and the diagnostics are logged already, e.g.
+	// the error is reused from the validation error cache
+	decorated bool
+}
+
+// decorate attaches the source and constraints of the template to the field errors and logs the diagnostics, it
+// takes effect once
+func (e *CueValidationError) decorate(template string) {
+	if e.decorated {
+		return
+	}
+	e.attachSource(template)
+	e.attachConstraints(template)
+	e.logDiagnostics()
+	e.decorated = true
+}
+
+// deepCopy copies the error including its field errors, so that the copy can be modified without affecting the
+// original, e.g. the one kept in the validation error cache
+func (e *CueValidationError) deepCopy() *CueValidationError {
+	out := *e
+	out.UserErrors = slices.Clone(e.UserErrors)
+	out.ParameterErrors = copyFieldErrors(e.ParameterErrors)
+	out.TemplateErrors = copyFieldErrors(e.TemplateErrors)
+	out.Diagnostics = slices.Clone(e.Diagnostics)
+	return &out
+}
+
+func copyFieldErrors(errs []FieldError) []FieldError {
+	if errs == nil {
+		return nil
+	}
+	out := make([]FieldError, len(errs))
+	for i, e := range errs {
+		out[i] = e
+		out[i].Disjuncts = copyFieldErrors(e.Disjuncts)
+		out[i].positions = slices.Clone(e.positions)
+	}
+	return out
+}
+
+// ErrorFormatter converts the CUE errors into the FieldErrors of CueValidationError and formats its message. The
+// DefaultErrorFormatter is used unless another implementation is registered, e.g.
+//
+//	registry.RegisterAs[definition.ErrorFormatter](&myFormatter{})
+//
+// Implementations may embed DefaultErrorFormatter to override only one of the methods.
+type ErrorFormatter interface {
+	// FieldError extracts the code, path, constraint and provided value of a single CUE error
+	FieldError(err cueerrors.Error) FieldError
+	// Format returns the message of the validation error
+	Format(err *CueValidationError) string
+}
+
+// DefaultErrorFormatter formats the errors grouped by their source according to the verbosity of the error
+type DefaultErrorFormatter struct{}
+
+// FieldError implements ErrorFormatter
+func (DefaultErrorFormatter) FieldError(err cueerrors.Error) FieldError {
+	return newFieldError(err)
+}
+
+// Format implements ErrorFormatter
+func (DefaultErrorFormatter) Format(err *CueValidationError) string {
+	return err.format()
+}
+
+func errorFormatter() ErrorFormatter {
+	if formatter, ok := registry.Get[ErrorFormatter](); ok {
+		return formatter
+	}
+	return DefaultErrorFormatter{}
+}
+
+// NewCueValidationError builds the CueValidationError from the CUE error and the errors reported by the template
+// itself. If a value is given, its concrete validation errors are collected as well.
+func NewCueValidationError(err error, messagePrefix string, entityType, entityName string, userErrors []string, val ...*cue.Value) *CueValidationError {
+	verr := &CueValidationError{
+		Prefix:     messagePrefix,
+		Verbosity:  DefaultErrorVerbosity,
+		EntityType: entityType,
+		EntityName: entityName,
+		UserErrors: userErrors,
+	}
+	if err == nil {
+		return verr
+	}
+	errList := cueerrors.Errors(err)
+	if len(val) > 0 && val[0] != nil {
+		if concreteErr := val[0].Validate(cue.Concrete(true)); concreteErr != nil {
+			errList = append(errList, cueerrors.Errors(concreteErr)...)
+		}
+	}
+	formatter := errorFormatter()
+	seen := map[string]bool{}
+	for _, e := range errList {
+		fieldErr := formatter.FieldError(e)
+		fieldErr.positions = append([]token.Pos{e.Position()}, e.InputPositions()...)
+		if seen[fieldErr.Message] {
+			continue
+		}
+		seen[fieldErr.Message] = true
+		if strings.HasPrefix(fieldErr.Message, velaprocess.ParameterFieldName+".") {
+			verr.ParameterErrors = append(verr.ParameterErrors, fieldErr)
+		} else {
+			verr.TemplateErrors = append(verr.TemplateErrors, fieldErr)
+		}
+	}
+	// Sort errors for deterministic output
+	sortFieldErrors(verr.ParameterErrors)
+	sortFieldErrors(verr.TemplateErrors)
+	verr.ParameterErrors = foldDisjunctions(verr.ParameterErrors)
+	verr.TemplateErrors = foldDisjunctions(verr.TemplateErrors)
+	return verr
+}
+
+func sortFieldErrors(errs []FieldError) {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Message < errs[j].Message
+	})
+}
+
+// foldDisjunctions moves the errors of the branches under the empty disjunction error of their path. CUE reports
+// each failed branch as a separate error at the path of the disjunction or below it.
+func foldDisjunctions(errs []FieldError) []FieldError {
+	var disjunctions []int
+	for i := range errs {
+		if errs[i].Code == ErrorCodeEmptyDisjunction && errs[i].Path != "" {
+			disjunctions = append(disjunctions, i)
+		}
+	}
+	if len(disjunctions) == 0 {
+		return errs
+	}
+	// fold the deepest disjunctions first so that the nested ones become the branches of their parents
+	sort.SliceStable(disjunctions, func(i, j int) bool {
+		return pathDepth(errs[disjunctions[i]].Path) > pathDepth(errs[disjunctions[j]].Path)
+	})
+	folded := make([]bool, len(errs))
+	for _, i := range disjunctions {
+		if folded[i] {
+			continue
+		}
+		for j := range errs {
+			if j == i || folded[j] || !isSubPath(errs[j].Path, errs[i].Path) {
+				continue
+			}
+			if errs[j].Code == ErrorCodeEmptyDisjunction && errs[j].Path == errs[i].Path {
+				continue
+			}
+			errs[i].Disjuncts = append(errs[i].Disjuncts, errs[j])
+			folded[j] = true
+		}
+		markClosestDisjunct(errs[i].Disjuncts, errs[i].Path)
+	}
+	var result []FieldError
+	for i := range errs {
+		if !folded[i] {
+			result = append(result, errs[i])
+		}
+	}
+	return result
+}
+
+// markClosestDisjunct marks the branch which is closest to be satisfied. A branch failing deeper in the value
+// matched more of its structure, a bound or a value of the same type is closer than a value of another type.
+// Nothing is marked if the best score is shared by several branches.
+func markClosestDisjunct(disjuncts []FieldError, path string) {
+	best, closest := -1, -1
+	for i, d := range disjuncts {
+		score := (pathDepth(d.Path) - pathDepth(path)) * 10
+		switch {
+		case d.Code == ErrorCodeOutOfBound:
+			score += 2
+		case d.Code == ErrorCodeConflictingValues && d.ExpectedType == "", d.Code == ErrorCodeIncompleteValue:
+			score++
+		}
+		switch {
+		case score > best:
+			best, closest = score, i
+		case score == best:
+			closest = -1
+		}
+	}
+	if closest >= 0 {
+		disjuncts[closest].Closest = true
+	}
+}
+
+func pathDepth(path string) int {
+	if path == "" {
+		return 0
+	}
+	return strings.Count(path, ".") + 1
+}
+
+func isSubPath(path, parent string) bool {
+	return path == parent || strings.HasPrefix(path, parent+".")
+}
+
+func newFieldError(e cueerrors.Error) FieldError {
+	msg := e.Error()
+	path := strings.Join(e.Path(), ".")
+	fieldErr := FieldError{Code: ErrorCodeInvalidValue, Path: path, Message: msg}
+	detail := msg
+	if path != "" {
+		detail = strings.TrimPrefix(msg, path+": ")
+	}
+	switch {
+	case outOfBoundRegex.MatchString(detail):
+		m := outOfBoundRegex.FindStringSubmatch(detail)
+		fieldErr.Code, fieldErr.Provided, fieldErr.Constraint = ErrorCodeOutOfBound, m[1], m[2]
+	case conflictingValuesRegex.MatchString(detail):
+		m := conflictingValuesRegex.FindStringSubmatch(detail)
+		fieldErr.Code, fieldErr.Constraint, fieldErr.Provided, fieldErr.ExpectedType = ErrorCodeConflictingValues, m[1], m[2], m[3]
+	case incompleteValueRegex.MatchString(detail):
+		m := incompleteValueRegex.FindStringSubmatch(detail)
+		fieldErr.Code, fieldErr.ExpectedType = ErrorCodeIncompleteValue, m[1]
+	case emptyDisjunctionRegex.MatchString(detail):
+		fieldErr.Code = ErrorCodeEmptyDisjunction
+	case strings.Contains(detail, "field not allowed"):
+		fieldErr.Code = ErrorCodeFieldNotAllowed
+	}
+	return fieldErr
+}
+
+// attachSource locates the field errors in the template, the first position of each error inside the template is
+// used since the template is compiled ahead of the parameter and the context
+func (e *CueValidationError) attachSource(template string) {
+	if template == "" {
+		return
+	}
+	lines := strings.Split(template, "\n")
+	var attach func(errs []FieldError)
+	attach = func(errs []FieldError) {
+		for i := range errs {
+			for _, pos := range errs[i].positions {
+				if !pos.IsValid() || pos.Line() < 1 || pos.Line() > len(lines) {
+					continue
+				}
+				errs[i].Line, errs[i].Column = pos.Line(), pos.Column()
+				errs[i].Snippet = formatSnippet(lines, pos.Line(), pos.Column())
+				break
+			}
+			attach(errs[i].Disjuncts)
+		}
+	}
+	attach(e.ParameterErrors)
+	attach(e.TemplateErrors)
+}
+
+// formatSnippet formats the line of the template with the lines around it, a caret is put under the column
+func formatSnippet(lines []string, line, column int) string {
+	var sb strings.Builder
+	width := len(fmt.Sprint(min(line+1, len(lines))))
+	for l := max(line-1, 1); l <= min(line+1, len(lines)); l++ {
+		sb.WriteString(fmt.Sprintf("%*d | %s\n", width, l, lines[l-1]))
+		if l != line {
+			continue
+		}
+		// keep the tabs of the line so that the caret is aligned
+		var indent strings.Builder
+		for i, r := range lines[l-1] {
+			if i >= column-1 {
+				break
+			}
+			if r == '\t' {
+				indent.WriteRune('\t')
+			} else {
+				indent.WriteRune(' ')
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%*s | %s^\n", width, "", indent.String()))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// diagnostics describes the information extracted from each CUE error, one line per field error
+func (e *CueValidationError) diagnostics() []string {
+	var lines []string
+	for _, fieldErr := range append(append([]FieldError{}, e.ParameterErrors...), e.TemplateErrors...) {
+		line := fmt.Sprintf("%s: code=%s", fieldErr.Path, fieldErr.Code)
+		for _, detail := range []struct{ name, value string }{
+			{"constraint", fieldErr.Constraint},
+			{"provided", fieldErr.Provided},
+			{"expectedType", fieldErr.ExpectedType},
+			{"default", fieldErr.Default},
+		} {
+			if detail.value != "" {
+				line += fmt.Sprintf(" %s=%q", detail.name, detail.value)
+			}
+		}
+		if fieldErr.Line > 0 {
+			line += fmt.Sprintf(" at=%d:%d", fieldErr.Line, fieldErr.Column)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// logDiagnostics logs the information extracted from each CUE error at the verbosity 4
+func (e *CueValidationError) logDiagnostics() {
+	logger := klog.V(4)
+	if !logger.Enabled() {
+		return
+	}
+	for _, fieldErr := range append(append([]FieldError{}, e.ParameterErrors...), e.TemplateErrors...) {
+		logger.InfoS("Extracted CUE validation error", "entityType", e.EntityType, "entityName", e.EntityName,
+			"path", fieldErr.Path, "code", fieldErr.Code, "constraint", fieldErr.Constraint, "provided", fieldErr.Provided,
+			"expectedType", fieldErr.ExpectedType, "default", fieldErr.Default, "line", fieldErr.Line, "column", fieldErr.Column)
+	}
+}
+
+// HasErrors returns whether any error is reported
+func (e *CueValidationError) HasErrors() bool {
+	return len(e.UserErrors) > 0 || len(e.ParameterErrors) > 0 || len(e.TemplateErrors) > 0
+}
+
+// Error formats the errors with the registered ErrorFormatter
+func (e *CueValidationError) Error() string {
+	return errorFormatter().Format(e)
+}
+
+// format formats the errors according to the verbosity, the grouped format is used by default
+func (e *CueValidationError) format() string {
+	if e.Verbosity == ErrorVerbosityCompact {
+		return e.compactError()
+	}
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("%s %s %s:", e.Prefix, e.EntityType, e.EntityName))
+
+	if len(e.UserErrors) > 0 {
+		result.WriteString("\n\nUser Errors:\n")
+		for _, msg := range e.UserErrors {
+			result.WriteString(fmt.Sprintf("  %s\n", msg))
+		}
+	}
+	if len(e.ParameterErrors) > 0 {
+		result.WriteString("\n\nParameter errors:\n")
+		for _, fieldErr := range e.ParameterErrors {
+			result.WriteString(e.formatFieldError(fieldErr))
+		}
+	}
+	if len(e.TemplateErrors) > 0 {
+		result.WriteString("\n\nTemplate errors:\n")
+		for _, fieldErr := range e.TemplateErrors {
+			result.WriteString(e.formatFieldError(fieldErr))
+		}
+	}
+	if len(e.Diagnostics) > 0 {
+		result.WriteString("\n\nDiagnostics:\n")
+		for _, line := range e.Diagnostics {
+			result.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+	}
+	return strings.TrimRight(result.String(), "\n")
+}
+
+func (e *CueValidationError) formatFieldError(fieldErr FieldError) string {
+	s := "  " + fieldErr.Message + "\n" + formatDisjuncts(fieldErr, "    ")
+	if e.Verbosity != ErrorVerbosityVerbose {
+		return s
+	}
+	for _, detail := range []struct{ name, value string }{
+		{"code", string(fieldErr.Code)},
+		{"constraint", fieldErr.Constraint},
+		{"provided", fieldErr.Provided},
+		{"expected type", fieldErr.ExpectedType},
+		{"default", fieldErr.Default},
+	} {
+		if detail.value != "" {
+			s += fmt.Sprintf("    %s: %s\n", detail.name, detail.value)
+		}
+	}
+	if fieldErr.Snippet != "" {
+		s += fmt.Sprintf("    at line %d, column %d:\n", fieldErr.Line, fieldErr.Column)
+		for _, line := range strings.Split(fieldErr.Snippet, "\n") {
+			s += "      " + line + "\n"
+		}
+	}
+	return s
+}
+
+// formatDisjuncts renders the branches of an empty disjunction as a tree, the closest branch is highlighted
+func formatDisjuncts(fieldErr FieldError, indent string) string {
+	var s string
+	for i, d := range fieldErr.Disjuncts {
+		branch, next := "├─ ", "│  "
+		if i == len(fieldErr.Disjuncts)-1 {
+			branch, next = "└─ ", "   "
+		}
+		msg := d.Message
+		if d.Path == fieldErr.Path {
+			msg = strings.TrimPrefix(msg, d.Path+": ")
+		}
+		if d.Closest {
+			msg += " (closest match)"
+		}
+		s += indent + branch + msg + "\n" + formatDisjuncts(d, indent+next)
+	}
+	return s
+}
+
+// compactError formats all the errors in one line, the field errors are identified by their paths
+func (e *CueValidationError) compactError() string {
+	var msgs []string
+	msgs = append(msgs, e.UserErrors...)
+	for _, fieldErr := range append(append([]FieldError{}, e.ParameterErrors...), e.TemplateErrors...) {
+		msg := strings.TrimSuffix(fieldErr.Message, ":")
+		for _, d := range fieldErr.Disjuncts {
+			if d.Closest {
+				msg += fmt.Sprintf(" (closest: %s)", d.Message)
+			}
+		}
+		msgs = append(msgs, msg)
+	}
+	return fmt.Sprintf("%s %s %s: %s", e.Prefix, e.EntityType, e.EntityName, strings.Join(msgs, "; "))
+}
+
+// MarshalJSON implements json.Marshaler, the formatted message is included for convenience
+func (e *CueValidationError) MarshalJSON() ([]byte, error) {
+	type alias CueValidationError
+	return json.Marshal(struct {
+		*alias
+		Message string `json:"message"`
+	}{alias: (*alias)(e), Message: e.Error()})
+}
+
+// ParameterPaths returns the distinct paths of the offending parameters in order, at most n paths if n > 0
+func (e *CueValidationError) ParameterPaths(n int) []string {
+	var paths []string
+	for _, fieldErr := range e.ParameterErrors {
+		if fieldErr.Path == "" || slices.Contains(paths, fieldErr.Path) {
+			continue
+		}
+		paths = append(paths, fieldErr.Path)
+		if n > 0 && len(paths) == n {
+			break
+		}
+	}
+	return paths
+}
+
+// ValidationErrorRecorder receives the validation failures of the definitions, it allows the callers to publish
+// them, e.g. as the events and conditions of the application
+type ValidationErrorRecorder interface {
+	RecordValidationError(err *CueValidationError)
+}
+
+// RecordValidationError passes the CueValidationError wrapped in err to the recorder, it returns whether one is found
+func RecordValidationError(recorder ValidationErrorRecorder, err error) bool {
+	var verr *CueValidationError
+	if recorder == nil || !errors.As(err, &verr) {
+		return false
+	}
+	recorder.RecordValidationError(verr)
+	return true
+}
+
+// ValidationErrors is the validation errors of the definitions rendered for one component
+type ValidationErrors []*CueValidationError
+
+// Error joins the messages of the validation errors
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n\n")
+}
+
+// ComponentValidationErrors is the validation errors of a component and its traits
+type ComponentValidationErrors struct {
+	Component string                `json:"component"`
+	Errors    []*CueValidationError `json:"errors"`
+}
+
+// AggregateValidationError reports the validation errors of all the components of an application in one pass
+type AggregateValidationError struct {
+	Components []ComponentValidationErrors `json:"components"`
+}
+
+// Add adds the validation errors wrapped in err to the component, it returns false if err is not a validation error
+func (e *AggregateValidationError) Add(component string, err error) bool {
+	var errs ValidationErrors
+	var verr *CueValidationError
+	switch {
+	case errors.As(err, &errs):
+	case errors.As(err, &verr):
+		errs = ValidationErrors{verr}
+	default:
+		return false
+	}
+	for i := range e.Components {
+		if e.Components[i].Component == component {
+			e.Components[i].Errors = append(e.Components[i].Errors, errs...)
+			return true
+		}
+	}
+	e.Components = append(e.Components, ComponentValidationErrors{Component: component, Errors: errs})
+	return true
+}
+
+// HasErrors returns whether any error is reported
+func (e *AggregateValidationError) HasErrors() bool {
+	return len(e.Components) > 0
+}
+
+// Error formats the validation errors grouped by component
+func (e *AggregateValidationError) Error() string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("validation failed for %d component(s):", len(e.Components)))
+	for _, comp := range e.Components {
+		result.WriteString(fmt.Sprintf("\n\ncomponent %s:", comp.Component))
+		for _, err := range comp.Errors {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				if line != "" {
+					line = "  " + line
+				}
+				result.WriteString("\n" + line)
+			}
+		}
+	}
+	return result.String()
+}
+
+// FormatCUEError formats CUE errors in a user-friendly grouped format, the returned error is a *CueValidationError
+func FormatCUEError(err error, messagePrefix string, entityType, entityName string, val ...*cue.Value) error {
+	if err == nil {
+		return nil
+	}
+	verr := NewCueValidationError(err, messagePrefix, entityType, entityName, nil, val...)
+	if !verr.HasErrors() {
+		return nil
+	}
+	return verr
+}
+
+// userError is an entry of the errs field declared in the template, the entry can also be a plain string
+type userError struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// decodeUserErrors decodes the errs field of the template into the fatal errors and the warnings
+func decodeUserErrors(errs cue.Value) (userErrors []string, warnings []string, err error) {
+	var entries []json.RawMessage
+	if err = errs.Decode(&entries); err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		var msg string
+		if json.Unmarshal(entry, &msg) == nil {
+			userErrors = append(userErrors, msg)
+			continue
+		}
+		var ue userError
+		if err = json.Unmarshal(entry, &ue); err != nil {
+			return nil, nil, err
+		}
+		switch ue.Severity {
+		case SeverityWarning:
+			warnings = append(warnings, ue.Message)
+		case "", SeverityError:
+			userErrors = append(userErrors, ue.Message)
+		default:
+			return nil, nil, fmt.Errorf("unknown severity %q of error %q", ue.Severity, ue.Message)
+		}
+	}
+	return userErrors, warnings, nil
+}
+
+func appendRenderWarnings(ctx process.Context, entityType, entityName string, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	all := GetRenderWarnings(ctx)
+	for _, warning := range warnings {
+		all = append(all, fmt.Sprintf("%s %s: %s", entityType, entityName, warning))
+	}
+	ctx.PushData(RenderWarningsKey, all)
+}
+
+// GetRenderWarnings returns the warnings reported by the errs field of the templates rendered in the context
+func GetRenderWarnings(ctx process.Context) []string {
+	warnings, _ := ctx.GetData(RenderWarningsKey).([]string)
+	return warnings
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	cueerrors "cuelang.org/go/cue/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/registry"
+)
+
+func TestCueValidationError(t *testing.T) {
+	template := `
+parameter: {
+	name: string
+	replicas: int & >=1
+	protocol: "TCP" | "UDP"
+}
+output: {
+	apiVersion: "apps/v1"
+	kind: "Deployment"
+	metadata: name: parameter.name
+	spec: replicas: parameter.replicas
+	spec: protocol: parameter.protocol
+}
+errs: ["custom error"]
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp"})
+	err := NewWorkloadAbstractEngine("my-workload").Complete(ctx, template, map[string]interface{}{
+		"name":     123,
+		"replicas": -1,
+		"protocol": "INVALID",
+	})
+	require.Error(t, err)
+	var verr *CueValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "workload", verr.EntityType)
+	require.Equal(t, "my-workload", verr.EntityName)
+	require.Equal(t, []string{"custom error"}, verr.UserErrors)
+
+	byPath := map[string]FieldError{}
+	for _, fieldErr := range verr.ParameterErrors {
+		if _, found := byPath[fieldErr.Path]; !found {
+			fieldErr.Snippet, fieldErr.positions = "", nil
+			byPath[fieldErr.Path] = fieldErr
+		}
+	}
+	require.Equal(t, FieldError{
+		Code:         ErrorCodeConflictingValues,
+		Path:         "parameter.name",
+		Message:      "parameter.name: conflicting values string and 123 (mismatched types string and int)",
+		Constraint:   "string",
+		Provided:     "123",
+		ExpectedType: "string",
+		Line:         3,
+		Column:       8,
+	}, byPath["parameter.name"])
+	require.Equal(t, FieldError{
+		Code:         ErrorCodeOutOfBound,
+		Path:         "parameter.replicas",
+		Message:      "parameter.replicas: invalid value -1 (out of bound >=1)",
+		Constraint:   ">=1",
+		Provided:     "-1",
+		ExpectedType: "int",
+		Line:         4,
+		Column:       18,
+	}, byPath["parameter.replicas"])
+	require.Equal(t, ErrorCodeEmptyDisjunction, byPath["parameter.protocol"].Code)
+	require.Equal(t, `"TCP" | "UDP"`, byPath["parameter.protocol"].Constraint)
+
+	bs, err := json.Marshal(verr)
+	require.NoError(t, err)
+	out := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(bs, &out))
+	require.Equal(t, verr.Error(), out["message"])
+	require.Equal(t, "my-workload", out["entityName"])
+	require.Len(t, out["parameterErrors"], len(verr.ParameterErrors))
+	require.NotContains(t, out, "templateErrors")
+
+	verr.Verbosity = ErrorVerbosityVerbose
+	require.Contains(t, verr.Error(), `    at line 4, column 18:
+      3 | 	name: string
+      4 | 	replicas: int & >=1
+        | 	                ^
+      5 | 	protocol: "TCP" | "UDP"`)
+}
+
+func TestFormatCUEErrorNil(t *testing.T) {
+	require.NoError(t, FormatCUEError(nil, "validation failed for", "trait", "t"))
+}
+
+func TestErrsSeverity(t *testing.T) {
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	wd := NewWorkloadAbstractEngine("worker")
+	require.NoError(t, wd.Complete(ctx, `
+output: {apiVersion: "v1", kind: "ConfigMap"}
+errs: [{message: "parameter image is deprecated", severity: "warning"}]
+`, nil))
+	td := NewTraitAbstractEngine("scaler")
+	require.NoError(t, td.Complete(ctx, `
+patch: metadata: labels: a: "b"
+errs: [if parameter.replicas > 5 {message: "too many replicas", severity: "warning"}]
+`, map[string]interface{}{"replicas": 10}))
+	require.Equal(t, []string{
+		"workload worker: parameter image is deprecated",
+		"trait scaler: too many replicas",
+	}, GetRenderWarnings(ctx))
+
+	err := td.Complete(ctx, `
+patch: metadata: labels: a: "b"
+errs: ["plain error", {message: "fatal error"}, {message: "minor issue", severity: "warning"}]
+`, nil)
+	var verr *CueValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, []string{"plain error", "fatal error"}, verr.UserErrors)
+	require.Len(t, GetRenderWarnings(ctx), 3)
+}
+
+func TestErrorVerbosity(t *testing.T) {
+	template := `
+parameter: replicas: int & >=1
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	spec: replicas: parameter.replicas
+}
+errs: ["replicas must be set explicitly"]
+`
+	render := func(opts ...EngineOption) string {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+		err := NewWorkloadAbstractEngine("web", opts...).Complete(ctx, template, map[string]interface{}{"replicas": 0})
+		require.Error(t, err)
+		return err.Error()
+	}
+
+	standard := render()
+	require.Contains(t, standard, "\n\nParameter errors:\n  parameter.replicas: invalid value 0 (out of bound >=1)")
+	require.NotContains(t, standard, "constraint:")
+
+	compact := render(WithErrorVerbosity(ErrorVerbosityCompact))
+	require.NotContains(t, compact, "\n")
+	require.Equal(t, "validation failed for workload web: replicas must be set explicitly; parameter.replicas: invalid value 0 (out of bound >=1)", compact)
+
+	verbose := render(WithErrorVerbosity(ErrorVerbosityVerbose))
+	require.Contains(t, verbose, "  parameter.replicas: invalid value 0 (out of bound >=1)\n    code: OutOfBound\n    constraint: >=1\n    provided: 0")
+
+	require.Equal(t, ErrorVerbosityStandard, parseErrorVerbosity(""))
+	require.Equal(t, ErrorVerbosityStandard, parseErrorVerbosity("unknown"))
+	require.Equal(t, ErrorVerbosityCompact, parseErrorVerbosity("compact"))
+}
+
+func TestErrorDiagnostics(t *testing.T) {
+	template := `
+parameter: replicas: int & >=1
+output: spec: replicas: parameter.replicas
+`
+	render := func(opts ...EngineOption) *CueValidationError {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+		err := NewWorkloadAbstractEngine("web", opts...).Complete(ctx, template, map[string]interface{}{"replicas": 0})
+		var verr *CueValidationError
+		require.True(t, errors.As(err, &verr))
+		return verr
+	}
+
+	verr := render()
+	require.Empty(t, verr.Diagnostics)
+	require.NotContains(t, verr.Error(), "Diagnostics:")
+
+	verr = render(WithDiagnostics(true))
+	require.Equal(t, []string{`parameter.replicas: code=OutOfBound constraint=">=1" provided="0" expectedType="int" at=2:28`}, verr.Diagnostics)
+	require.Contains(t, verr.Error(), "\n\nDiagnostics:\n  parameter.replicas: code=OutOfBound")
+	bs, err := json.Marshal(verr)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `"diagnostics":["parameter.replicas: code=OutOfBound`)
+}
+
+type docsErrorFormatter struct {
+	DefaultErrorFormatter
+}
+
+func (f docsErrorFormatter) FieldError(err cueerrors.Error) FieldError {
+	fieldErr := f.DefaultErrorFormatter.FieldError(err)
+	fieldErr.Message += fmt.Sprintf(" (see https://docs.example.com/errors/%s)", fieldErr.Code)
+	return fieldErr
+}
+
+func (docsErrorFormatter) Format(err *CueValidationError) string {
+	bs, _ := json.Marshal(struct {
+		EntityName      string       `json:"entityName"`
+		ParameterErrors []FieldError `json:"parameterErrors"`
+	}{EntityName: err.EntityName, ParameterErrors: err.ParameterErrors})
+	return string(bs)
+}
+
+func TestErrorFormatter(t *testing.T) {
+	snapshot := registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(snapshot) })
+	registry.RegisterAs[ErrorFormatter](docsErrorFormatter{})
+
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "web", Namespace: "default"})
+	err := NewWorkloadAbstractEngine("web").Complete(ctx, `
+parameter: replicas: int & >=1
+output: spec: replicas: parameter.replicas
+`, map[string]interface{}{"replicas": 0})
+	require.Error(t, err)
+	var verr *CueValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.ParameterErrors, 1)
+	require.Equal(t, ErrorCodeOutOfBound, verr.ParameterErrors[0].Code)
+	require.Equal(t, 2, verr.ParameterErrors[0].Line)
+	out := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(err.Error()), &out))
+	require.Equal(t, "web", out["entityName"])
+	require.Contains(t, err.Error(), "(see https://docs.example.com/errors/OutOfBound)")
+}
+
+func TestDisjunctionErrorTree(t *testing.T) {
+	verr := &CueValidationError{
+		Prefix:     "validation failed for",
+		EntityType: "workload",
+		EntityName: "web",
+		ParameterErrors: foldDisjunctions([]FieldError{
+			{Code: ErrorCodeEmptyDisjunction, Path: "parameter.port", Message: "parameter.port: 2 errors in empty disjunction:"},
+			{Code: ErrorCodeConflictingValues, Path: "parameter.port", ExpectedType: "int",
+				Message: "parameter.port: conflicting values int and {name:\"http\",number:\"80\"} (mismatched types int and struct)"},
+			{Code: ErrorCodeConflictingValues, Path: "parameter.port.number", ExpectedType: "int",
+				Message: "parameter.port.number: conflicting values int and \"80\" (mismatched types int and string)"},
+			{Code: ErrorCodeOutOfBound, Path: "parameter.replicas", Message: "parameter.replicas: invalid value 0 (out of bound >=1)"},
+		}),
+	}
+	require.Len(t, verr.ParameterErrors, 2)
+	require.Len(t, verr.ParameterErrors[0].Disjuncts, 2)
+	require.False(t, verr.ParameterErrors[0].Disjuncts[0].Closest)
+	require.True(t, verr.ParameterErrors[0].Disjuncts[1].Closest)
+	require.Equal(t, `validation failed for workload web:
+
+Parameter errors:
+  parameter.port: 2 errors in empty disjunction:
+    ├─ conflicting values int and {name:"http",number:"80"} (mismatched types int and struct)
+    └─ parameter.port.number: conflicting values int and "80" (mismatched types int and string) (closest match)
+  parameter.replicas: invalid value 0 (out of bound >=1)`, verr.Error())
+
+	verr.Verbosity = ErrorVerbosityCompact
+	require.Equal(t, `validation failed for workload web: parameter.port: 2 errors in empty disjunction `+
+		`(closest: parameter.port.number: conflicting values int and "80" (mismatched types int and string)); `+
+		`parameter.replicas: invalid value 0 (out of bound >=1)`, verr.Error())
+
+	// the branches failing alike are not ranked
+	errs := foldDisjunctions([]FieldError{
+		{Code: ErrorCodeEmptyDisjunction, Path: "parameter.protocol", Message: "parameter.protocol: 2 errors in empty disjunction:"},
+		{Code: ErrorCodeConflictingValues, Path: "parameter.protocol", Message: `parameter.protocol: conflicting values "TCP" and "HTTP"`},
+		{Code: ErrorCodeConflictingValues, Path: "parameter.protocol", Message: `parameter.protocol: conflicting values "UDP" and "HTTP"`},
+	})
+	require.Len(t, errs, 1)
+	for _, d := range errs[0].Disjuncts {
+		require.False(t, d.Closest)
+	}
+}
@@ -19,6 +19,7 @@ package health
 import (
 	"encoding/json"
 	"slices"
+	"sort"
 	"strings"
 
 	"cuelang.org/go/cue"
@@ -34,6 +35,11 @@ import (
 const (
 	CustomMessage  = "message"
 	IsHealthPolicy = "isHealth"
+	// HealthPoliciesField declares the health expressions of the output and each named output, which are
+	// aggregated into the health of the whole component
+	HealthPoliciesField = "healthPolicies"
+	// HealthThresholdField declares the min weighted ratio of the healthy resources
+	HealthThresholdField = "healthThreshold"
 )
 
 type StatusRequest struct {
@@ -47,6 +53,25 @@ type StatusResult struct {
 	Healthy bool              `json:"healthy"`
 	Message string            `json:"message,omitempty"`
 	Details map[string]string `json:"details,omitempty"`
+	// Score the weighted ratio of the healthy resources, only set with healthPolicies
+	Score *float64 `json:"score,omitempty"`
+	// Resources the health of each resource, only set with healthPolicies
+	Resources []ResourceHealth `json:"resources,omitempty"`
+}
+
+// ResourceHealth is the health of a single resource evaluated by healthPolicies
+type ResourceHealth struct {
+	Name     string  `json:"name"`
+	Healthy  bool    `json:"healthy"`
+	Required bool    `json:"required"`
+	Weight   float64 `json:"weight"`
+}
+
+// healthPolicy is an entry of healthPolicies, the resource is required and weighted 1 by default
+type healthPolicy struct {
+	IsHealth bool     `json:"isHealth"`
+	Weight   *float64 `json:"weight,omitempty"`
+	Required *bool    `json:"required,omitempty"`
 }
 
 func CheckHealth(templateContext map[string]interface{}, healthPolicyTemplate string, parameter interface{}) (bool, error) {
@@ -77,7 +102,10 @@ func GetStatus(templateContext map[string]interface{}, request *StatusRequest) (
 		klog.Warningf("failed to get status map: %v", mapErr)
 	}
 
-	healthy, healthErr := CheckHealth(templateContext, request.Health, request.Parameter)
+	healthy, score, resources, aggregated, healthErr := checkAggregatedHealth(templateContext, request.Health, request.Parameter)
+	if !aggregated {
+		healthy, healthErr = CheckHealth(templateContext, request.Health, request.Parameter)
+	}
 	if healthErr != nil {
 		klog.Warningf("failed to check health: %v", healthErr)
 	}
@@ -93,11 +121,83 @@ func GetStatus(templateContext map[string]interface{}, request *StatusRequest) (
 		klog.Warningf("failed to get status message: %v", msgErr)
 	}
 
-	return &StatusResult{
+	result := &StatusResult{
 		Healthy: healthy,
 		Message: message,
 		Details: statusMap,
-	}, nil
+	}
+	if aggregated {
+		result.Score, result.Resources = &score, resources
+	}
+	return result, nil
+}
+
+// checkAggregatedHealth evaluates the healthPolicies of the health policy template, aggregated is false if the
+// template doesn't declare them. All the required resources must be healthy and the weighted ratio of the healthy
+// resources must reach the healthThreshold if declared. The resources are sorted by name with the output first.
+func checkAggregatedHealth(templateContext map[string]interface{}, healthPolicyTemplate string, parameter interface{}) (healthy bool, score float64, resources []ResourceHealth, aggregated bool, err error) {
+	if !strings.Contains(healthPolicyTemplate, HealthPoliciesField) {
+		return false, 0, nil, false, nil
+	}
+	runtimeContextBuff, err := formatRuntimeContext(templateContext, parameter)
+	if err != nil {
+		return false, 0, nil, false, err
+	}
+	val := cuecontext.New().CompileString(healthPolicyTemplate + "\n" + runtimeContextBuff)
+	policies := val.LookupPath(value.FieldPath(HealthPoliciesField))
+	if !policies.Exists() {
+		return false, 0, nil, false, nil
+	}
+	var policyMap map[string]healthPolicy
+	if err = policies.Decode(&policyMap); err != nil {
+		return false, 0, nil, true, errors.WithMessage(err, "evaluate health policies")
+	}
+
+	healthy = true
+	var total, healthyWeight float64
+	for name, policy := range policyMap {
+		rh := ResourceHealth{Name: name, Healthy: policy.IsHealth, Required: true, Weight: 1}
+		if policy.Required != nil {
+			rh.Required = *policy.Required
+		}
+		if policy.Weight != nil {
+			rh.Weight = *policy.Weight
+		}
+		total += rh.Weight
+		if rh.Healthy {
+			healthyWeight += rh.Weight
+		} else if rh.Required {
+			healthy = false
+		}
+		resources = append(resources, rh)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if (resources[i].Name == "output") != (resources[j].Name == "output") {
+			return resources[i].Name == "output"
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	score = 1
+	if total > 0 {
+		score = healthyWeight / total
+	}
+
+	if threshold := val.LookupPath(value.FieldPath(HealthThresholdField)); threshold.Exists() {
+		t, err := threshold.Float64()
+		if err != nil {
+			return false, score, resources, true, errors.WithMessage(err, "evaluate health threshold")
+		}
+		healthy = healthy && score >= t
+	}
+	// the top level isHealth is honored together with the healthPolicies
+	if isHealth := val.LookupPath(value.FieldPath(IsHealthPolicy)); isHealth.Exists() {
+		h, err := isHealth.Bool()
+		if err != nil {
+			return false, score, resources, true, errors.WithMessage(err, "evaluate health status")
+		}
+		healthy = healthy && h
+	}
+	return healthy, score, resources, true, nil
 }
 
 func getStatusMessage(templateContext map[string]interface{}, customStatusTemplate string, parameter interface{}) (string, error) {
@@ -411,6 +411,80 @@ func TestGetStatus(t *testing.T) {
 	}
 }
 
+func TestGetAggregatedStatus(t *testing.T) {
+	tpContext := map[string]interface{}{
+		"output": map[string]interface{}{
+			"status": map[string]interface{}{"replicas": 3, "readyReplicas": 3},
+		},
+		"outputs": map[string]interface{}{
+			"service": map[string]interface{}{"spec": map[string]interface{}{"clusterIP": "10.0.0.1"}},
+			"ingress": map[string]interface{}{"status": map[string]interface{}{}},
+		},
+	}
+	policies := `
+healthPolicies: {
+	output: isHealth: context.output.status.readyReplicas == context.output.status.replicas
+	service: {
+		isHealth: context.outputs.service.spec.clusterIP != ""
+		weight:   2
+	}
+	ingress: {
+		isHealth: context.outputs.ingress.status.loadBalancer != _|_
+		required: false
+	}
+}
+`
+	cases := map[string]struct {
+		health  string
+		healthy bool
+		score   float64
+	}{
+		"optional resource unhealthy": {
+			health:  policies,
+			healthy: true,
+			score:   0.75,
+		},
+		"threshold not reached": {
+			health:  policies + "healthThreshold: 0.8",
+			healthy: false,
+			score:   0.75,
+		},
+		"threshold reached": {
+			health:  policies + "healthThreshold: 0.5",
+			healthy: true,
+			score:   0.75,
+		},
+		"top level isHealth": {
+			health:  policies + "isHealth: false",
+			healthy: false,
+			score:   0.75,
+		},
+		"required resource unhealthy": {
+			health:  strings.Replace(policies, "required: false", "required: true", 1),
+			healthy: false,
+			score:   0.75,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := GetStatus(tpContext, &StatusRequest{Health: c.health})
+			assert.NoError(t, err)
+			assert.Equal(t, c.healthy, result.Healthy)
+			assert.NotNil(t, result.Score)
+			assert.Equal(t, c.score, *result.Score)
+			assert.Equal(t, []string{"output", "ingress", "service"}, []string{result.Resources[0].Name, result.Resources[1].Name, result.Resources[2].Name})
+			assert.False(t, result.Resources[1].Healthy)
+			assert.Equal(t, float64(2), result.Resources[2].Weight)
+		})
+	}
+
+	result, err := GetStatus(tpContext, &StatusRequest{Health: "isHealth: true"})
+	assert.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Nil(t, result.Score)
+	assert.Nil(t, result.Resources)
+}
+
 func TestContextPassing(t *testing.T) {
 	cases := map[string]struct {
 		initialCtx  map[string]interface{}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+)
+
+var (
+	// MaxRenderOutputs the max number of outputs rendered by a single template, 0 means no limit
+	MaxRenderOutputs int
+	// MaxOutputBytes the max size in bytes of the JSON encoding of a single rendered object, 0 means no limit
+	MaxOutputBytes int
+)
+
+// checkOutputCount rejects the rendering of more outputs than MaxRenderOutputs, which is usually caused by a
+// runaway comprehension
+func checkOutputCount(count int, prefix string) error {
+	if MaxRenderOutputs > 0 && count > MaxRenderOutputs {
+		return errors.Errorf("%s renders more than %d outputs, exceeding the limit of rendered outputs", prefix, MaxRenderOutputs)
+	}
+	return nil
+}
+
+// checkOutputSize rejects the rendered object whose JSON encoding is larger than MaxOutputBytes
+func checkOutputSize(v cue.Value, name string) error {
+	if MaxOutputBytes <= 0 {
+		return nil
+	}
+	bt, err := v.MarshalJSON()
+	if err != nil {
+		// leave the incomplete value to the model
+		return nil
+	}
+	if len(bt) > MaxOutputBytes {
+		return errors.Errorf("%s is %d bytes, exceeding the limit of %d bytes of a rendered object", name, len(bt), MaxOutputBytes)
+	}
+	return nil
+}
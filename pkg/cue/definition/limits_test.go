@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+func TestRenderLimits(t *testing.T) {
+	origOutputs, origBytes := MaxRenderOutputs, MaxOutputBytes
+	t.Cleanup(func() { MaxRenderOutputs, MaxOutputBytes = origOutputs, origBytes })
+
+	template := `
+import "list"
+
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: value: parameter.value
+}
+outputs: [for i in list.Range(0, parameter.count, 1) {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	metadata: name: "cm-\(i)"
+}]
+parameter: {
+	count: int
+	value: string
+}
+`
+	render := func(count int, value string) error {
+		ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+		return NewWorkloadAbstractEngine("comp").Complete(ctx, template, map[string]interface{}{"count": count, "value": value})
+	}
+
+	require.NoError(t, render(10, "small"))
+
+	MaxRenderOutputs = 3
+	require.NoError(t, render(3, "small"))
+	require.ErrorContains(t, render(4, "small"), "comp renders more than 3 outputs")
+
+	MaxOutputBytes = 100
+	require.NoError(t, render(1, "small"))
+	err := render(1, strings.Repeat("a", 100))
+	require.ErrorContains(t, err, "output of workload comp is")
+	require.ErrorContains(t, err, "exceeding the limit of 100 bytes")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"strings"
+
+	"github.com/kubevela/workflow/pkg/cue/process"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifests returns the objects rendered in the context, the output comes first followed by the outputs in the
+// order they are rendered. Unlike the parsing of the workloads and traits, no label or annotation is added.
+func Manifests(ctx process.Context) ([]*unstructured.Unstructured, error) {
+	if ctx == nil {
+		return nil, nil
+	}
+	base, auxiliaries := ctx.Output()
+	var manifests []*unstructured.Unstructured
+	if base != nil {
+		obj, err := base.Unstructured()
+		if err != nil {
+			return nil, errors.WithMessage(err, "output")
+		}
+		manifests = append(manifests, obj)
+	}
+	for _, aux := range auxiliaries {
+		obj, err := aux.Ins.Unstructured()
+		if err != nil {
+			return nil, errors.WithMessage(err, auxiliaryOutputName(aux))
+		}
+		manifests = append(manifests, obj)
+	}
+	return manifests, nil
+}
+
+// ManifestsYAML returns the objects rendered in the context as a multi-document YAML
+func ManifestsYAML(ctx process.Context) (string, error) {
+	manifests, err := Manifests(ctx)
+	if err != nil {
+		return "", err
+	}
+	docs := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		bt, err := yaml.Marshal(manifest.Object)
+		if err != nil {
+			return "", errors.Wrapf(err, "marshal %s %s", manifest.GetKind(), manifest.GetName())
+		}
+		docs = append(docs, string(bt))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// auxiliaryOutputName returns outputs.<name> for the outputs of the workloads and <trait>.outputs.<name> for the
+// outputs of the traits
+func auxiliaryOutputName(aux process.Auxiliary) string {
+	name := "outputs." + aux.Name
+	if aux.Type != "" {
+		name = aux.Type + "." + name
+	}
+	return name
+}
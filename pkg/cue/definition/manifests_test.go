@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+func TestManifests(t *testing.T) {
+	template := `
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: context.name
+}
+outputs: service: {
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: name: context.name
+}
+`
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(ctx, template, nil))
+
+	manifests, err := Manifests(ctx)
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	require.Equal(t, "Deployment", manifests[0].GetKind())
+	require.Equal(t, "Service", manifests[1].GetKind())
+	require.Equal(t, "comp", manifests[1].GetName())
+
+	s, err := ManifestsYAML(ctx)
+	require.NoError(t, err)
+	docs := strings.Split(s, "---\n")
+	require.Len(t, docs, 2)
+	require.Contains(t, docs[0], "kind: Deployment")
+	require.Contains(t, docs[1], "kind: Service")
+
+	manifests, err = Manifests(nil)
+	require.NoError(t, err)
+	require.Empty(t, manifests)
+}
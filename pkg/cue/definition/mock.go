@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubevela/pkg/cue/cuex"
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+)
+
+type mockConfigsKey struct{}
+
+type mockProvidersKey struct{}
+
+// MockConfigs are the properties of the configs keyed by <namespace>/<name>, it implements ConfigReader
+type MockConfigs map[string]map[string]interface{}
+
+// ReadConfig implements ConfigReader
+func (m MockConfigs) ReadConfig(_ context.Context, namespace, name string) (map[string]interface{}, error) {
+	config, found := m[namespace+"/"+name]
+	if !found {
+		return nil, fmt.Errorf("mock config %s/%s not found", namespace, name)
+	}
+	return config, nil
+}
+
+// WithMockConfigs returns a context in which the parameter defaults of the templates are read from the mock configs
+// instead of the registered ConfigReader, so that dry-runs and tests render without a cluster
+func WithMockConfigs(ctx context.Context, configs MockConfigs) context.Context {
+	return context.WithValue(ctx, mockConfigsKey{}, configs)
+}
+
+// WithMockProviders returns a context in which the templates are compiled with the given packages in place of the
+// packages of the same import paths, e.g. a vela/kube package whose functions return fixtures.
+func WithMockProviders(ctx context.Context, pkgs ...cuexruntime.Package) context.Context {
+	return context.WithValue(ctx, mockProvidersKey{}, pkgs)
+}
+
+func getMockConfigReader(ctx context.Context) (ConfigReader, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	configs, ok := ctx.Value(mockConfigsKey{}).(MockConfigs)
+	return configs, ok
+}
+
+// getMockCompiler returns the compiler loading the mock packages of the context together with the registered
+// packages imported by the template
+func getMockCompiler(ctx context.Context, template string) (*cuex.Compiler, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	mocks, _ := ctx.Value(mockProvidersKey{}).([]cuexruntime.Package)
+	if len(mocks) == 0 {
+		return nil, false
+	}
+	loadImportedPackages(template)
+	packagesMu.RLock()
+	var pkgs []cuexruntime.Package
+	for _, path := range getRegisteredImports(template) {
+		pkgs = append(pkgs, registeredPackages[path])
+	}
+	packagesMu.RUnlock()
+	return newCompiler(append(pkgs, mocks...)...), true
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/registry"
+)
+
+func newEchoPackage(t *testing.T, fn func(string) string) cuexruntime.Package {
+	pkg, err := cuexruntime.NewInternalPackage("org/echo", `
+package echo
+#Echo: {
+	#do:       "echo"
+	#provider: "org/echo"
+	input:   string
+	output?: string
+}
+`, map[string]cuexruntime.ProviderFn{
+		"echo": cuexruntime.GenericProviderFn[echoVars, map[string]string](func(_ context.Context, in *echoVars) (*map[string]string, error) {
+			return &map[string]string{"output": fn(in.Input)}, nil
+		}),
+	})
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestMockProviders(t *testing.T) {
+	RegisterPackage(newEchoPackage(t, strings.ToUpper))
+	t.Cleanup(func() { UnregisterPackage("org/echo") })
+
+	template := `
+import "vela/org/echo"
+
+echoed: echo.#Echo & {input: parameter.name}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: echoed.output
+}
+`
+	render := func(ctx context.Context) string {
+		pCtx := process.NewContext(process.ContextData{Ctx: ctx, AppName: "app", CompName: "comp", Namespace: "default"})
+		require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(pCtx, template, map[string]interface{}{"name": "val"}))
+		base, _ := pCtx.Output()
+		s, err := base.String()
+		require.NoError(t, err)
+		return s
+	}
+
+	require.Contains(t, render(context.Background()), `name: "VAL"`)
+	mocked := WithMockProviders(context.Background(), newEchoPackage(t, func(string) string { return "mocked" }))
+	require.Contains(t, render(mocked), `name: "mocked"`)
+	require.Contains(t, render(context.Background()), `name: "VAL"`)
+}
+
+func TestMockConfigs(t *testing.T) {
+	snapshot := registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(snapshot) })
+	registry.Restore(registry.RegistrySnapshot{})
+
+	template := `
+parameterDefaults: config: "defaults"
+parameter: {
+	image:    string
+	registry: string
+}
+output: {
+	apiVersion: "v1"
+	kind:       "Pod"
+	spec: containers: [{image: parameter.registry + "/" + parameter.image}]
+}
+`
+	ctx := WithMockConfigs(context.Background(), MockConfigs{"vela-system/defaults": {"registry": "mock.io"}})
+	pCtx := process.NewContext(process.ContextData{Ctx: ctx, AppName: "app", CompName: "comp", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(pCtx, template, map[string]interface{}{"image": "nginx"}))
+	base, _ := pCtx.Output()
+	s, err := base.String()
+	require.NoError(t, err)
+	require.Contains(t, s, `image: "mock.io/nginx"`)
+
+	ctx = WithMockConfigs(context.Background(), MockConfigs{})
+	pCtx = process.NewContext(process.ContextData{Ctx: ctx, AppName: "app", CompName: "comp", Namespace: "default"})
+	err = NewWorkloadAbstractEngine("comp").Complete(pCtx, template, map[string]interface{}{"image": "nginx"})
+	require.ErrorContains(t, err, "mock config vela-system/defaults not found")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ParameterSchemaPath is the path serving the OpenAPI v3 document of the parameters of the installed definitions
+const ParameterSchemaPath = "/openapi/v3/definitions"
+
+// DefaultParameterSchemaCatalog is the catalog served by the controller, it is refreshed from the informers of the
+// definitions on every replica
+var DefaultParameterSchemaCatalog = NewParameterSchemaCatalog()
+
+// ParameterSchemaCatalog keeps the parameter schemas of the installed definitions and describes them in a single
+// OpenAPI v3 document, so that the portals can render the forms of the definitions without parsing CUE. The
+// schemas are named as <kind>.<namespace>.<name>, e.g. ComponentDefinition.vela-system.webservice.
+type ParameterSchemaCatalog struct {
+	mu       sync.RWMutex
+	schemas  map[string]*openapi3.Schema
+	revision int64
+}
+
+// NewParameterSchemaCatalog creates an empty catalog
+func NewParameterSchemaCatalog() *ParameterSchemaCatalog {
+	return &ParameterSchemaCatalog{schemas: map[string]*openapi3.Schema{}}
+}
+
+// ParameterSchemaName returns the name of the schema of a definition in the OpenAPI document
+func ParameterSchemaName(kind, namespace, name string) string {
+	return fmt.Sprintf("%s.%s.%s", kind, namespace, name)
+}
+
+// Set generates the parameter schema of the template and adds or replaces the schema of the definition
+func (c *ParameterSchemaCatalog) Set(ctx context.Context, kind, namespace, name, template string) error {
+	schema, err := GenerateParameterSchema(ctx, template)
+	if err != nil {
+		return err
+	}
+	if schema.Title == "" {
+		schema.Title = name
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas[ParameterSchemaName(kind, namespace, name)] = schema
+	c.revision++
+	return nil
+}
+
+// Delete removes the schema of the definition
+func (c *ParameterSchemaCatalog) Delete(kind, namespace, name string) {
+	key := ParameterSchemaName(kind, namespace, name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.schemas[key]; !found {
+		return
+	}
+	delete(c.schemas, key)
+	c.revision++
+}
+
+// Document builds the OpenAPI v3 document, the version of the document is bumped on every change of the schemas
+func (c *ParameterSchemaCatalog) Document() *openapi3.T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	schemas := make(openapi3.Schemas, len(c.schemas))
+	for name, schema := range c.schemas {
+		schemas[name] = openapi3.NewSchemaRef("", schema)
+	}
+	return &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "KubeVela Definition Parameters",
+			Description: "The parameter schemas of the installed definitions",
+			Version:     strconv.FormatInt(c.revision, 10),
+		},
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+}
+
+// ServeHTTP writes the OpenAPI v3 document in JSON
+func (c *ParameterSchemaCatalog) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	bt, err := json.Marshal(c.Document())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bt)
+}
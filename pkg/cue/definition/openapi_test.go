@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterSchemaCatalog(t *testing.T) {
+	ctx := context.Background()
+	catalog := NewParameterSchemaCatalog()
+	require.NoError(t, catalog.Set(ctx, "ComponentDefinition", "vela-system", "worker", `
+parameter: {
+	image: string
+	cmd?: [...string]
+}
+output: {kind: "Deployment"}
+`))
+	require.NoError(t, catalog.Set(ctx, "TraitDefinition", "vela-system", "scaler", `
+parameter: replicas: *1 | int
+patch: spec: replicas: parameter.replicas
+`))
+	require.Error(t, catalog.Set(ctx, "TraitDefinition", "vela-system", "broken", `parameter: {`))
+
+	doc := catalog.Document()
+	require.Equal(t, "2", doc.Info.Version)
+	require.Len(t, doc.Components.Schemas, 2)
+	worker := doc.Components.Schemas[ParameterSchemaName("ComponentDefinition", "vela-system", "worker")].Value
+	require.Equal(t, "worker", worker.Title)
+	require.Contains(t, worker.Properties, "image")
+	require.NoError(t, doc.Validate(ctx))
+
+	catalog.Delete("TraitDefinition", "vela-system", "scaler")
+	catalog.Delete("TraitDefinition", "vela-system", "not-found")
+	doc = catalog.Document()
+	require.Equal(t, "3", doc.Info.Version)
+	require.Len(t, doc.Components.Schemas, 1)
+
+	rec := httptest.NewRecorder()
+	catalog.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ParameterSchemaPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	loaded, err := openapi3.NewLoader().LoadFromData(rec.Body.Bytes())
+	require.NoError(t, err)
+	require.Contains(t, loaded.Components.Schemas, ParameterSchemaName("ComponentDefinition", "vela-system", "worker"))
+}
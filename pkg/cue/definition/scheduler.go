@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ScheduleFieldName is the name of the definition declaring the rendering schedule of a trait, it's a definition
+	// so that it is neither rendered nor mixed up with the fields of the template
+	ScheduleFieldName = "#schedule"
+	// StageFieldName is the name of the field of the schedule declaring the rendering stage of a trait
+	StageFieldName = "stage"
+	// DependsOnFieldName is the name of the field of the schedule declaring the traits to be rendered before a trait
+	DependsOnFieldName = "dependsOn"
+
+	// TraitStagePreWorkload the trait is rendered before the other traits
+	TraitStagePreWorkload = "pre-workload"
+	// TraitStagePostWorkload the trait is rendered after the other traits
+	TraitStagePostWorkload = "post-workload"
+	// traitStageAfterPrefix the prefix of the stage declaring the trait is rendered after another trait, i.e. after:<trait>
+	traitStageAfterPrefix = "after:"
+)
+
+// TraitSchedule is the rendering order declared by a trait template
+type TraitSchedule struct {
+	// Stage the rank of the trait, 0 for pre-workload, 1 for default and 2 for post-workload
+	Stage int
+	// DependsOn the types of the traits which must be rendered before the trait
+	DependsOn []string
+}
+
+// GetTraitSchedule parses the `stage` and `dependsOn` fields of the `#schedule` of the trait template, e.g.
+//
+//	#schedule: {
+//		stage: "post-workload"
+//		dependsOn: ["scaler"]
+//	}
+//
+// Only literal values are honored, so that the schedule can be decided before the parameters are filled.
+func GetTraitSchedule(template string) (TraitSchedule, error) {
+	schedule := TraitSchedule{Stage: 1}
+	f, err := parseTemplate(template)
+	if err != nil {
+		// leave the syntax error to the rendering
+		return schedule, nil
+	}
+	for _, decl := range f.Decls {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if label, _, err := ast.LabelName(field.Label); err != nil || label != ScheduleFieldName {
+			continue
+		}
+		st, ok := field.Value.(*ast.StructLit)
+		if !ok {
+			continue
+		}
+		if err := parseTraitSchedule(st, &schedule); err != nil {
+			return schedule, err
+		}
+	}
+	return schedule, nil
+}
+
+func parseTraitSchedule(st *ast.StructLit, schedule *TraitSchedule) error {
+	for _, elt := range st.Elts {
+		field, ok := elt.(*ast.Field)
+		if !ok {
+			continue
+		}
+		label, _, err := ast.LabelName(field.Label)
+		if err != nil {
+			continue
+		}
+		switch label {
+		case StageFieldName:
+			stage, ok := stringLit(field.Value)
+			if !ok {
+				continue
+			}
+			switch {
+			case stage == TraitStagePreWorkload:
+				schedule.Stage = 0
+			case stage == TraitStagePostWorkload:
+				schedule.Stage = 2
+			case strings.HasPrefix(stage, traitStageAfterPrefix) && len(stage) > len(traitStageAfterPrefix):
+				schedule.DependsOn = append(schedule.DependsOn, strings.TrimPrefix(stage, traitStageAfterPrefix))
+			default:
+				return fmt.Errorf("invalid trait stage %q in %s, must be one of %s, %s or %s<trait>", stage, ScheduleFieldName, TraitStagePreWorkload, TraitStagePostWorkload, traitStageAfterPrefix)
+			}
+		case DependsOnFieldName:
+			list, ok := field.Value.(*ast.ListLit)
+			if !ok {
+				continue
+			}
+			for _, elt := range list.Elts {
+				if dep, ok := stringLit(elt); ok {
+					schedule.DependsOn = append(schedule.DependsOn, dep)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// ScheduleTraits returns the indices of the traits in rendering order. names and templates are the types and the
+// templates of the traits attached to a component. Dependencies take precedence over stages, traits in the same
+// stage keep their original order, and dependencies on traits not attached to the component are ignored.
+func ScheduleTraits(names []string, templates []string) ([]int, error) {
+	schedules := make([]TraitSchedule, len(names))
+	indices := map[string][]int{}
+	for i, name := range names {
+		schedule, err := GetTraitSchedule(templates[i])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "trait %s", name)
+		}
+		schedules[i] = schedule
+		indices[name] = append(indices[name], i)
+	}
+
+	inDegree := make([]int, len(names))
+	dependents := make([][]int, len(names))
+	for i, schedule := range schedules {
+		for _, dep := range schedule.DependsOn {
+			for _, j := range indices[dep] {
+				if j == i {
+					continue
+				}
+				dependents[j] = append(dependents[j], i)
+				inDegree[i]++
+			}
+		}
+	}
+
+	var ready, order []int
+	for i := range names {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool {
+			if schedules[ready[a]].Stage != schedules[ready[b]].Stage {
+				return schedules[ready[a]].Stage < schedules[ready[b]].Stage
+			}
+			return ready[a] < ready[b]
+		})
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, next)
+		for _, i := range dependents[next] {
+			inDegree[i]--
+			if inDegree[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+	}
+
+	if len(order) < len(names) {
+		var cycle []string
+		for i, degree := range inDegree {
+			if degree > 0 {
+				cycle = append(cycle, names[i])
+			}
+		}
+		return nil, fmt.Errorf("cyclic dependency between traits: %s", strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
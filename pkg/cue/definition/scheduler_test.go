@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTraitSchedule(t *testing.T) {
+	testCases := map[string]struct {
+		template string
+		schedule TraitSchedule
+		hasErr   bool
+	}{
+		"default": {
+			template: `patch: spec: replicas: parameter.replicas`,
+			schedule: TraitSchedule{Stage: 1},
+		},
+		"pre-workload": {
+			template: `#schedule: stage: "pre-workload"
+patch: {}`,
+			schedule: TraitSchedule{Stage: 0},
+		},
+		"post-workload with dependsOn": {
+			template: `#schedule: {
+	stage: "post-workload"
+	dependsOn: ["scaler", "labels"]
+}`,
+			schedule: TraitSchedule{Stage: 2, DependsOn: []string{"scaler", "labels"}},
+		},
+		"after": {
+			template: `#schedule: stage: "after:scaler"`,
+			schedule: TraitSchedule{Stage: 1, DependsOn: []string{"scaler"}},
+		},
+		"non-literal stage is ignored": {
+			template: `#schedule: stage: parameter.stage`,
+			schedule: TraitSchedule{Stage: 1},
+		},
+		"top-level fields are not the schedule": {
+			template: `stage: "unknown"
+dependsOn: ["scaler"]`,
+			schedule: TraitSchedule{Stage: 1},
+		},
+		"invalid stage": {
+			template: `#schedule: stage: "unknown"`,
+			hasErr:   true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			schedule, err := GetTraitSchedule(tc.template)
+			if tc.hasErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.schedule, schedule)
+		})
+	}
+}
+
+func TestScheduleTraits(t *testing.T) {
+	testCases := map[string]struct {
+		names     []string
+		templates []string
+		order     []int
+		err       string
+	}{
+		"keep original order": {
+			names:     []string{"a", "b", "c"},
+			templates: []string{``, ``, ``},
+			order:     []int{0, 1, 2},
+		},
+		"stages": {
+			names:     []string{"a", "b", "c"},
+			templates: []string{`#schedule: stage: "post-workload"`, ``, `#schedule: stage: "pre-workload"`},
+			order:     []int{2, 1, 0},
+		},
+		"dependencies take precedence over stages": {
+			names: []string{"a", "b", "c"},
+			templates: []string{`#schedule: {
+	stage: "pre-workload"
+	dependsOn: ["c"]
+}`, ``, `#schedule: stage: "post-workload"`},
+			order: []int{1, 2, 0},
+		},
+		"after": {
+			names:     []string{"a", "b"},
+			templates: []string{`#schedule: stage: "after:b"`, ``},
+			order:     []int{1, 0},
+		},
+		"missing dependency is ignored": {
+			names:     []string{"a", "b"},
+			templates: []string{`#schedule: dependsOn: ["x"]`, ``},
+			order:     []int{0, 1},
+		},
+		"cycle": {
+			names:     []string{"a", "b", "c"},
+			templates: []string{`#schedule: dependsOn: ["b"]`, `#schedule: stage: "after:a"`, ``},
+			err:       "cyclic dependency between traits: a, b",
+		},
+		"invalid stage": {
+			names:     []string{"a"},
+			templates: []string{`#schedule: stage: "x"`},
+			err:       "trait a",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			order, err := ScheduleTraits(tc.names, tc.templates)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.order, order)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/encoding/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/kubevela/pkg/cue/cuex"
+	"github.com/pkg/errors"
+
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+const (
+	// JSONSchemaDialect is the dialect declared by the JSON Schemas of the parameters, the schema objects generated by
+	// the CUE OpenAPI encoder only use the keywords of the JSON Schema draft 4
+	JSONSchemaDialect = "http://json-schema.org/draft-04/schema#"
+
+	// usageTag and shortTag are the comment tags describing the parameters, see the docgen of the definitions
+	usageTag = "+usage="
+	shortTag = "+short"
+)
+
+// GenerateParameterSchema converts the parameter of the template into a schema, the defaults, enums, bounds and
+// patterns declared by the CUE constraints are kept. The schema of an empty object is returned if the template
+// declares no parameter.
+func GenerateParameterSchema(ctx context.Context, template string) (*openapi3.Schema, error) {
+	val, err := GetCompiler(template).CompileStringWithOptions(ctx, renderTemplate(template), cuex.DisableResolveProviderFunctions{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "compile template")
+	}
+	paramVal := val.LookupPath(cue.ParsePath(velaprocess.ParameterFieldName))
+	if !paramVal.Exists() || paramVal.IncompleteKind() == cue.TopKind {
+		return openapi3.NewObjectSchema(), nil
+	}
+	doc := val.Context().CompileString("{}").FillPath(cue.MakePath(cue.Def(velaprocess.ParameterFieldName)), paramVal)
+	bt, err := generateOpenAPI(doc)
+	if err != nil {
+		return nil, errors.WithMessage(err, "generate schema of parameter")
+	}
+	var components struct {
+		Components struct {
+			Schemas map[string]*openapi3.Schema `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(bt, &components); err != nil {
+		return nil, err
+	}
+	schema, ok := components.Components.Schemas[velaprocess.ParameterFieldName]
+	if !ok {
+		return nil, errors.New("no schema is generated for parameter")
+	}
+	cleanSchemaDescription(schema)
+	return schema, nil
+}
+
+// GenerateParameterJSONSchema returns the parameter of the template as a standalone JSON Schema document, it can be
+// consumed by the IDEs and the clients to complete and validate the properties of the Application components
+func GenerateParameterJSONSchema(ctx context.Context, entityType, entityName, template string) ([]byte, error) {
+	schema, err := GenerateParameterSchema(ctx, template)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "%s %s", entityType, entityName)
+	}
+	bt, err := schema.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(bt, &doc); err != nil {
+		return nil, err
+	}
+	doc["$schema"] = JSONSchemaDialect
+	doc["title"] = entityName
+	if _, found := doc["description"]; !found {
+		doc["description"] = fmt.Sprintf("Parameters of the %s %s", entityType, entityName)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// generateOpenAPI runs the CUE OpenAPI encoder, which panics on some unsupported constraints
+func generateOpenAPI(val cue.Value) (bt []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unsupported parameter schema: %v", r)
+		}
+	}()
+	return openapi.Gen(val, &openapi.Config{ExpandReferences: true})
+}
+
+// cleanSchemaDescription strips the comment tags from the descriptions of the schema and its properties
+func cleanSchemaDescription(schema *openapi3.Schema) {
+	if schema == nil {
+		return
+	}
+	for _, prop := range schema.Properties {
+		cleanSchemaDescription(prop.Value)
+	}
+	if schema.Items != nil {
+		cleanSchemaDescription(schema.Items.Value)
+	}
+	if schema.AdditionalProperties.Schema != nil {
+		cleanSchemaDescription(schema.AdditionalProperties.Schema.Value)
+	}
+	description := schema.Description
+	if _, usage, found := strings.Cut(description, usageTag); found {
+		description = usage
+	}
+	if short, _, found := strings.Cut(description, shortTag); found {
+		description = short
+	}
+	schema.Description = strings.TrimSpace(description)
+}
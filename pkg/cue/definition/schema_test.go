@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateParameterSchema(t *testing.T) {
+	template := `
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: context.name
+	spec: replicas: parameter.replicas
+}
+parameter: {
+	// +usage=Which image would you like to use for your service
+	// +short=i
+	image: string & =~"^[a-z0-9./:-]+$"
+	port: int & >=1 & <=65535
+	// +usage=Specify the number of replicas
+	replicas: *1 | int
+	protocol: *"TCP" | "UDP" | "SCTP"
+	env?: [...{name: string, value?: string}]
+}
+`
+	ctx := context.Background()
+	schema, err := GenerateParameterSchema(ctx, template)
+	require.NoError(t, err)
+	require.Contains(t, schema.Required, "image")
+	require.NotContains(t, schema.Required, "env")
+
+	image := schema.Properties["image"].Value
+	require.Equal(t, "^[a-z0-9./:-]+$", image.Pattern)
+	require.Equal(t, "Which image would you like to use for your service", image.Description)
+
+	port := schema.Properties["port"].Value
+	require.EqualValues(t, 1, *port.Min)
+	require.EqualValues(t, 65535, *port.Max)
+
+	replicas := schema.Properties["replicas"].Value
+	require.EqualValues(t, 1, replicas.Default)
+	require.Equal(t, "Specify the number of replicas", replicas.Description)
+
+	protocol := schema.Properties["protocol"].Value
+	require.Equal(t, "TCP", protocol.Default)
+	require.Equal(t, []interface{}{"TCP", "UDP", "SCTP"}, protocol.Enum)
+
+	env := schema.Properties["env"].Value
+	require.True(t, env.Type.Is("array"))
+	require.Contains(t, env.Items.Value.Properties, "name")
+
+	schema, err = GenerateParameterSchema(ctx, `output: {kind: "ConfigMap"}`)
+	require.NoError(t, err)
+	require.True(t, schema.Type.Is("object"))
+	require.Empty(t, schema.Properties)
+
+	_, err = GenerateParameterSchema(ctx, `parameter: {`)
+	require.Error(t, err)
+}
+
+func TestGenerateParameterJSONSchema(t *testing.T) {
+	bt, err := GenerateParameterJSONSchema(context.Background(), "trait", "scaler", `
+parameter: replicas: *1 | int
+patch: spec: replicas: parameter.replicas
+`)
+	require.NoError(t, err)
+	doc := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(bt, &doc))
+	require.Equal(t, JSONSchemaDialect, doc["$schema"])
+	require.Equal(t, "scaler", doc["title"])
+	require.Equal(t, "Parameters of the trait scaler", doc["description"])
+	require.Equal(t, "object", doc["type"])
+	require.Contains(t, doc["properties"], "replicas")
+}
@@ -29,10 +29,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/cue/definition/health"
 	"github.com/oam-dev/kubevela/pkg/features"
 
-	"github.com/kubevela/pkg/cue/cuex"
-
 	"cuelang.org/go/cue"
-	cueerrors "cuelang.org/go/cue/errors"
 	"github.com/kubevela/pkg/multicluster"
 
 	"github.com/pkg/errors"
@@ -59,8 +56,17 @@ const (
 	PatchFieldName = "patch"
 	// PatchOutputsFieldName is the name of the struct contains the patch of outputs CR data
 	PatchOutputsFieldName = "patchOutputs"
+	// PatchOutputsWildcard is the key of patchOutputs patching all the outputs
+	PatchOutputsWildcard = "*"
+	// PatchOutputsSelectorsFieldName is the name of the list in patchOutputs patching the outputs matched by type,
+	// apiVersion, kind or labels
+	PatchOutputsSelectorsFieldName = "$selectors"
 	// ErrsFieldName check if errors contained in the cue
 	ErrsFieldName = "errs"
+	// GCFieldName is the name of the field declaring the garbage collection policy of an output
+	GCFieldName = "$gc"
+	// RenderWarningsKey is the context key for storing the warnings reported by the templates
+	RenderWarningsKey = "render-warnings"
 	// TemplateContextPrefix is the base prefix for storing templates in context
 	TemplateContextPrefix = "template-context-"
 )
@@ -75,10 +81,17 @@ func GetTraitTemplateKey(name string) string {
 	return TemplateContextPrefix + "trait-" + name
 }
 
+// GetPolicyTemplateKey returns the context key for storing policy templates
+func GetPolicyTemplateKey(name string) string {
+	return TemplateContextPrefix + "policy-" + name
+}
+
 const (
 	// AuxiliaryWorkload defines the extra workload obj from a workloadDefinition,
 	// e.g. a workload composed by deployment and service, the service will be marked as AuxiliaryWorkload
 	AuxiliaryWorkload = "AuxiliaryWorkload"
+	// AuxiliaryPolicy defines the extra obj rendered from the outputs of a policyDefinition
+	AuxiliaryPolicy = "AuxiliaryPolicy"
 )
 
 // AbstractEngine defines Definition's Render interface
@@ -90,6 +103,64 @@ type AbstractEngine interface {
 
 type def struct {
 	name string
+	// offline makes the engine build the template context from the rendered resources
+	// held in the process context instead of reading them from the cluster
+	offline bool
+	// verbosity the verbosity of the validation errors, DefaultErrorVerbosity is used if empty
+	verbosity ErrorVerbosity
+	// diagnostics captures the information extracted from the CUE errors into the validation errors
+	diagnostics bool
+}
+
+// EngineOption configures the AbstractEngine created by the constructors
+type EngineOption func(*def)
+
+// WithOfflineRender builds the template context purely from the in-memory output and outputs,
+// so templates can be completed and evaluated without any cluster access, e.g. dry-run in CI
+func WithOfflineRender() EngineOption {
+	return func(d *def) {
+		d.offline = true
+	}
+}
+
+// WithErrorVerbosity sets the verbosity of the validation errors returned by the engine
+func WithErrorVerbosity(verbosity ErrorVerbosity) EngineOption {
+	return func(d *def) {
+		d.verbosity = verbosity
+	}
+}
+
+// WithDiagnostics captures the information extracted from the CUE errors, i.e. the code, constraint, provided value
+// and position of each field, into the validation errors returned by the engine. The information is always logged at
+// the verbosity 4.
+func WithDiagnostics(enabled bool) EngineOption {
+	return func(d *def) {
+		d.diagnostics = enabled
+	}
+}
+
+func newDef(name string, opts ...EngineOption) def {
+	d := def{name: name}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	return d
+}
+
+// getResource returns the live object of the rendered resource, or the rendered resource itself in offline mode
+func (d *def) getResource(ctx context.Context, pctx process.Context, obj *unstructured.Unstructured, cli client.Reader, namespace string, labels map[string]string, outputsResource string) (map[string]interface{}, error) {
+	if d.offline {
+		u := obj.DeepCopy()
+		if u.GetNamespace() == "" {
+			u.SetNamespace(namespace)
+		}
+		if outputsResource != "" {
+			labels[oam.TraitResource] = outputsResource
+		}
+		util.AddLabels(u, labels)
+		return u.Object, nil
+	}
+	return getResourceFromObj(ctx, pctx, obj, cli, namespace, labels, outputsResource)
 }
 
 type workloadDef struct {
@@ -97,21 +168,30 @@ type workloadDef struct {
 }
 
 // NewWorkloadAbstractEngine create Workload Definition AbstractEngine
-func NewWorkloadAbstractEngine(name string) AbstractEngine {
+func NewWorkloadAbstractEngine(name string, opts ...EngineOption) AbstractEngine {
 	return &workloadDef{
-		def: def{
-			name: name,
-		},
+		def: newDef(name, opts...),
 	}
 }
 
 // Complete do workload definition's rendering
 func (wd *workloadDef) Complete(ctx process.Context, abstractTemplate string, params interface{}) error {
+	return wd.decorateError(completeBaseTemplate(ctx, "workload", wd.name, AuxiliaryWorkload, GetWorkloadTemplateKey(wd.name), abstractTemplate, params), abstractTemplate)
+}
+
+// completeBaseTemplate renders a template whose `output` becomes the base object of the context
+// and whose `outputs` become auxiliaries of the given type, it's shared by workload and policy definitions.
+// The compiled template is pushed into the context under the templateKey.
+func completeBaseTemplate(ctx process.Context, entityType, name, auxiliaryType, templateKey string, abstractTemplate string, params interface{}) error {
+	params, err := ApplyParameterDefaults(ctx.GetCtx(), contextNamespace(ctx), abstractTemplate, params)
+	if err != nil {
+		return errors.WithMessagef(err, "%s %s", entityType, name)
+	}
 	var paramFile = velaprocess.ParameterFieldName + ": {}"
 	if params != nil {
 		bt, err := json.Marshal(params)
 		if err != nil {
-			return errors.WithMessagef(err, "marshal parameter of workload %s", wd.name)
+			return errors.WithMessagef(err, "marshal parameter of %s %s", entityType, name)
 		}
 		if string(bt) != "null" {
 			paramFile = fmt.Sprintf("%s: %s", velaprocess.ParameterFieldName, string(bt))
@@ -123,56 +203,42 @@ func (wd *workloadDef) Complete(ctx process.Context, abstractTemplate string, pa
 		return err
 	}
 
-	val, err := cuex.DefaultCompiler.Get().CompileString(ctx.GetCtx(), strings.Join([]string{
-		renderTemplate(abstractTemplate), paramFile, c,
-	}, "\n"))
+	val, err := compileTemplate(ctx.GetCtx(), renderTemplate(abstractTemplate), paramFile, c)
 
 	if err != nil {
-		return errors.WithMessagef(err, "failed to compile workload %s after merge parameter and context", wd.name)
+		return errors.WithMessagef(err, "failed to compile %s %s after merge parameter and context", entityType, name)
 	}
 
 	var userErrors []string
 	if errs := val.LookupPath(value.FieldPath(ErrsFieldName)); errs.Exists() {
-		if err := errs.Decode(&userErrors); err != nil {
-			klog.Warningf("Workload definition '%s' has malformed 'errs' field (expected []string): %v. Custom error reporting will be skipped.", wd.name, err)
+		var warnings []string
+		if userErrors, warnings, err = decodeUserErrors(errs); err != nil {
+			klog.Warningf("Definition '%s' of %s has malformed 'errs' field (expected []string): %v. Custom error reporting will be skipped.", name, entityType, err)
 		}
+		appendRenderWarnings(ctx, entityType, name, warnings)
 	}
 
 	validationErr := val.Validate()
 
 	if validationErr != nil || len(userErrors) > 0 {
-		var result strings.Builder
-		result.WriteString(fmt.Sprintf("validation failed for workload %s:", wd.name))
-
-		if len(userErrors) > 0 {
-			result.WriteString("\n\nUser Errors:\n")
-			for _, e := range userErrors {
-				result.WriteString(fmt.Sprintf("  %s\n", e))
-			}
-		}
-
-		if validationErr != nil {
-			if fmtErr := FormatCUEError(validationErr, "validation failed for", "workload", wd.name, &val); fmtErr != nil {
-				errMsg := fmtErr.Error()
-				errMsg = strings.TrimPrefix(errMsg, fmt.Sprintf("validation failed for workload %s:", wd.name))
-				result.WriteString(errMsg)
-			}
-		}
-
-		return errors.New(strings.TrimRight(result.String(), "\n"))
+		key := newValidationErrorCacheKey(entityType, name, abstractTemplate, params, c)
+		return newRenderValidationError(key, abstractTemplate, validationErr, userErrors, &val)
 	}
 	output := val.LookupPath(value.FieldPath(OutputFieldName))
+	if err := checkOutputSize(output, fmt.Sprintf("output of %s %s", entityType, name)); err != nil {
+		return err
+	}
 
 	base, err := model.NewBase(output)
 	if err != nil {
-		return errors.WithMessagef(err, "invalid output of workload %s", wd.name)
+		return errors.WithMessagef(err, "invalid output of %s %s", entityType, name)
 	}
 	if err := ctx.SetBase(base); err != nil {
 		return err
 	}
 
-	// Store template for error context (use workload-specific key to avoid pollution)
-	ctx.PushData(GetWorkloadTemplateKey(wd.name), val)
+	// Store template for error context (use entity-specific key to avoid pollution)
+	ctx.PushData(templateKey, val)
 
 	// we will support outputs for workload composition, and it will become trait in AppConfig.
 	outputs := val.LookupPath(value.FieldPath(OutputsFieldName))
@@ -180,26 +246,125 @@ func (wd *workloadDef) Complete(ctx process.Context, abstractTemplate string, pa
 		return nil
 	}
 
+	return iterateOutputs(outputs, name, func(outputName string, v cue.Value) error {
+		other, err := newOutput(v)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid outputs(%s) of %s %s", outputName, entityType, name)
+		}
+		return ctx.AppendAuxiliaries(process.Auxiliary{Ins: other, Type: auxiliaryType, Name: outputName})
+	})
+}
+
+// iterateOutputs calls fn on each output in declaration order. The outputs can be a struct, whose field labels
+// are used as the names, or a list generated by a comprehension, whose elements are named <prefix>-<index>.
+func iterateOutputs(outputs cue.Value, prefix string, fn func(name string, v cue.Value) error) error {
+	call := func(name string, v cue.Value) error {
+		v, err := applyGCPolicy(v)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid outputs(%s) of %s", name, prefix)
+		}
+		if err := checkOutputSize(v, fmt.Sprintf("outputs(%s) of %s", name, prefix)); err != nil {
+			return err
+		}
+		return fn(name, v)
+	}
+	if outputs.IncompleteKind() == cue.ListKind {
+		iter, err := outputs.List()
+		if err != nil {
+			return errors.WithMessagef(err, "invalid outputs of %s", prefix)
+		}
+		for i := 0; iter.Next(); i++ {
+			if err := checkOutputCount(i+1, prefix); err != nil {
+				return err
+			}
+			if err := call(fmt.Sprintf("%s-%d", prefix, i), iter.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	iter, err := outputs.Fields(cue.Definitions(true), cue.Hidden(true), cue.All())
 	if err != nil {
-		return errors.WithMessagef(err, "invalid outputs of workload %s", wd.name)
+		return errors.WithMessagef(err, "invalid outputs of %s", prefix)
+	}
+	type namedOutput struct {
+		name string
+		v    cue.Value
 	}
+	var named []namedOutput
 	for iter.Next() {
 		if iter.Selector().IsDefinition() || iter.Selector().PkgPath() != "" || iter.IsOptional() {
 			continue
 		}
-		other, err := model.NewOther(iter.Value())
-		name := util.GetIteratorLabel(*iter)
-		if err != nil {
-			return errors.WithMessagef(err, "invalid outputs(%s) of workload %s", name, wd.name)
-		}
-		if err := ctx.AppendAuxiliaries(process.Auxiliary{Ins: other, Type: AuxiliaryWorkload, Name: name}); err != nil {
+		named = append(named, namedOutput{name: util.GetIteratorLabel(*iter), v: iter.Value()})
+	}
+	if err := checkOutputCount(len(named), prefix); err != nil {
+		return err
+	}
+	// the field order of CUE is not guaranteed across versions, sort the outputs by name to keep the rendering stable
+	if feature.DefaultMutableFeatureGate.Enabled(features.EnableStableRenderOutputs) {
+		sort.SliceStable(named, func(i, j int) bool { return named[i].name < named[j].name })
+	}
+	for _, output := range named {
+		if err := call(output.name, output.v); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// applyGCPolicy copies the $gc field of the output into the gc-policy annotation. The field is kept in the value,
+// so that its hidden fields, closedness and definitions are preserved, and is dropped once encoded, see newOutput.
+func applyGCPolicy(v cue.Value) (cue.Value, error) {
+	gc := v.LookupPath(value.FieldPath(GCFieldName))
+	if !gc.Exists() {
+		return v, nil
+	}
+	policy, err := gc.String()
+	if err != nil {
+		return v, errors.WithMessagef(err, "invalid %s", GCFieldName)
+	}
+	switch policy {
+	case oam.GCPolicyOrphan, oam.GCPolicyCascade, oam.GCPolicyNever:
+	default:
+		return v, fmt.Errorf("invalid %s %q, must be one of %s, %s or %s", GCFieldName, policy, oam.GCPolicyOrphan, oam.GCPolicyCascade, oam.GCPolicyNever)
+	}
+	return v.FillPath(cue.ParsePath("metadata.annotations"), map[string]string{oam.AnnotationGCPolicy: policy}), nil
+}
+
+// gcOutput is an output declaring the $gc field, which is not part of the resource
+type gcOutput struct {
+	model.Instance
+}
+
+// Unstructured drops the $gc field from the resource
+func (o *gcOutput) Unstructured() (*unstructured.Unstructured, error) {
+	u, err := o.Instance.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	delete(u.Object, GCFieldName)
+	return u, nil
+}
+
+// Compile drops the $gc field from the resource
+func (o *gcOutput) Compile() ([]byte, error) {
+	u, err := o.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	return u.MarshalJSON()
+}
+
+// newOutput creates the instance of the output
+func newOutput(v cue.Value) (model.Instance, error) {
+	ins, err := model.NewOther(v)
+	if err != nil || !v.LookupPath(value.FieldPath(GCFieldName)).Exists() {
+		return ins, err
+	}
+	return &gcOutput{Instance: ins}, nil
+}
+
 func withCluster(ctx context.Context, o client.Object) context.Context {
 	if cluster := oam.GetCluster(o); cluster != "" {
 		return multicluster.WithCluster(ctx, cluster)
@@ -209,7 +374,7 @@ func withCluster(ctx context.Context, o client.Object) context.Context {
 
 func (wd *workloadDef) getTemplateContext(ctx process.Context, cli client.Reader, accessor util.NamespaceAccessor) (map[string]interface{}, error) {
 	baseLabels := GetBaseContextLabels(ctx)
-	var root = initRoot(baseLabels)
+	var root = initRoot(ctx, baseLabels)
 	var commonLabels = GetCommonLabels(baseLabels)
 
 	base, assists := ctx.Output()
@@ -219,7 +384,7 @@ func (wd *workloadDef) getTemplateContext(ctx process.Context, cli client.Reader
 	}
 	// workload main resource will have a unique label("app.oam.dev/resourceType"="WORKLOAD") in per component/app level
 	_ctx := withCluster(ctx.GetCtx(), componentWorkload)
-	object, err := getResourceFromObj(_ctx, ctx, componentWorkload, cli, accessor.For(componentWorkload), util.MergeMapOverrideWithDst(map[string]string{
+	object, err := wd.getResource(_ctx, ctx, componentWorkload, cli, accessor.For(componentWorkload), util.MergeMapOverrideWithDst(map[string]string{
 		oam.LabelOAMResourceType: oam.ResourceTypeWorkload,
 	}, commonLabels), "")
 	if err != nil {
@@ -240,7 +405,7 @@ func (wd *workloadDef) getTemplateContext(ctx process.Context, cli client.Reader
 		}
 		// AuxiliaryWorkload will have a unique label("trait.oam.dev/resource"="name of outputs") in per component/app level
 		_ctx := withCluster(ctx.GetCtx(), traitRef)
-		object, err := getResourceFromObj(_ctx, ctx, traitRef, cli, accessor.For(traitRef), util.MergeMapOverrideWithDst(map[string]string{
+		object, err := wd.getResource(_ctx, ctx, traitRef, cli, accessor.For(traitRef), util.MergeMapOverrideWithDst(map[string]string{
 			oam.TraitTypeLabel: AuxiliaryWorkload,
 		}, commonLabels), assist.Name)
 		if err != nil {
@@ -268,28 +433,27 @@ type traitDef struct {
 }
 
 // NewTraitAbstractEngine create Trait Definition AbstractEngine
-func NewTraitAbstractEngine(name string) AbstractEngine {
+func NewTraitAbstractEngine(name string, opts ...EngineOption) AbstractEngine {
 	return &traitDef{
-		def: def{
-			name: name,
-		},
+		def: newDef(name, opts...),
 	}
 }
 
 // Complete do trait definition's rendering
-// nolint:gocyclo
 func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, params interface{}) error {
-	buff := abstractTemplate + "\n"
-	if params != nil {
-		bt, err := json.Marshal(params)
-		if err != nil {
-			return errors.WithMessagef(err, "marshal parameter of trait %s", td.name)
-		}
-		if string(bt) != "null" {
-			buff += fmt.Sprintf("%s: %s\n", velaprocess.ParameterFieldName, string(bt))
-		}
+	c, err := td.contextFile(ctx)
+	if err != nil {
+		return err
 	}
+	val, key, err := td.compile(ctx.GetCtx(), contextNamespace(ctx), abstractTemplate, params, c)
+	if err != nil {
+		return err
+	}
+	return td.decorateError(td.apply(ctx, val, abstractTemplate, key), abstractTemplate)
+}
 
+// contextFile returns the base context the trait template is compiled with
+func (td *traitDef) contextFile(ctx process.Context) (string, error) {
 	multiStageEnabled := feature.DefaultMutableFeatureGate.Enabled(features.MultiStageComponentApply)
 	var statusBytes []byte
 	if multiStageEnabled {
@@ -298,7 +462,7 @@ func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, param
 
 	c, err := ctx.BaseContextFile()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// When multi-stage is enabled, merge the existing output.status from ctx into the
@@ -306,44 +470,60 @@ func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, param
 	if multiStageEnabled {
 		c = injectOutputStatusIntoBaseContext(ctx, c, statusBytes)
 	}
+	return c, nil
+}
 
-	buff += c
+// contextNamespace returns the namespace of the application rendered by the context
+func contextNamespace(ctx process.Context) string {
+	namespace, _ := ctx.GetData(model.ContextNamespace).(string)
+	return namespace
+}
 
-	val, err := cuex.DefaultCompiler.Get().CompileString(ctx.GetCtx(), buff)
+// compile merges the parameter and the base context into the trait template, it doesn't touch the process context
+// so that independent traits can be compiled concurrently. The returned key identifies the rendering in the
+// validation error cache.
+func (td *traitDef) compile(ctx context.Context, namespace, abstractTemplate string, params interface{}, c string) (cue.Value, validationErrorCacheKey, error) {
+	params, err := ApplyParameterDefaults(ctx, namespace, abstractTemplate, params)
+	if err != nil {
+		return cue.Value{}, validationErrorCacheKey{}, errors.WithMessagef(err, "trait %s", td.name)
+	}
+	key := newValidationErrorCacheKey("trait", td.name, abstractTemplate, params, c)
+	var paramFile string
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return cue.Value{}, key, errors.WithMessagef(err, "marshal parameter of trait %s", td.name)
+		}
+		if string(bt) != "null" {
+			paramFile = fmt.Sprintf("%s: %s", velaprocess.ParameterFieldName, string(bt))
+		}
+	}
 
+	val, err := compileTemplate(ctx, abstractTemplate, paramFile, c)
 	if err != nil {
-		return errors.WithMessagef(err, "failed to compile trait %s after merge parameter and context", td.name)
+		return cue.Value{}, key, errors.WithMessagef(err, "failed to compile trait %s after merge parameter and context", td.name)
 	}
+	return val, key, nil
+}
 
+// apply validates the compiled trait and applies its outputs and patches to the process context, the key identifies
+// the validation error of the trait in the validation error cache
+// nolint:gocyclo
+func (td *traitDef) apply(ctx process.Context, val cue.Value, abstractTemplate string, key validationErrorCacheKey) error {
+	var err error
 	var userErrors []string
 	if errs := val.LookupPath(value.FieldPath(ErrsFieldName)); errs.Exists() {
-		if err := errs.Decode(&userErrors); err != nil {
+		var warnings []string
+		if userErrors, warnings, err = decodeUserErrors(errs); err != nil {
 			klog.Warningf("Trait definition '%s' has malformed 'errs' field (expected []string): %v. Custom error reporting will be skipped.", td.name, err)
 		}
+		appendRenderWarnings(ctx, "trait", td.name, warnings)
 	}
 
 	validationErr := val.Validate()
 
 	if validationErr != nil || len(userErrors) > 0 {
-		var result strings.Builder
-		result.WriteString(fmt.Sprintf("validation failed for trait %s:", td.name))
-
-		if len(userErrors) > 0 {
-			result.WriteString("\n\nUser Errors:\n")
-			for _, e := range userErrors {
-				result.WriteString(fmt.Sprintf("  %s\n", e))
-			}
-		}
-
-		if validationErr != nil {
-			if fmtErr := FormatCUEError(validationErr, "validation failed for", "trait", td.name, &val); fmtErr != nil {
-				errMsg := fmtErr.Error()
-				errMsg = strings.TrimPrefix(errMsg, fmt.Sprintf("validation failed for trait %s:", td.name))
-				result.WriteString(errMsg)
-			}
-		}
-
-		return errors.New(strings.TrimRight(result.String(), "\n"))
+		return newRenderValidationError(key, abstractTemplate, validationErr, userErrors, &val)
 	}
 
 	processing := val.LookupPath(value.FieldPath("processing"))
@@ -354,23 +534,15 @@ func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, param
 	}
 	outputs := val.LookupPath(value.FieldPath(OutputsFieldName))
 	if outputs.Exists() {
-
-		iter, err := outputs.Fields(cue.Definitions(true), cue.Hidden(true), cue.All())
-		if err != nil {
-			return errors.WithMessagef(err, "invalid outputs of trait %s", td.name)
-		}
-		for iter.Next() {
-			if iter.Selector().IsDefinition() || iter.Selector().PkgPath() != "" || iter.IsOptional() {
-				continue
-			}
-			other, err := model.NewOther(iter.Value())
-			name := util.GetIteratorLabel(*iter)
+		err := iterateOutputs(outputs, td.name, func(name string, v cue.Value) error {
+			other, err := newOutput(v)
 			if err != nil {
 				return errors.WithMessagef(err, "invalid outputs(resource=%s) of trait %s", name, td.name)
 			}
-			if err := ctx.AppendAuxiliaries(process.Auxiliary{Ins: other, Type: td.name, Name: name}); err != nil {
-				return err
-			}
+			return ctx.AppendAuxiliaries(process.Auxiliary{Ins: other, Type: td.name, Name: name})
+		})
+		if err != nil {
+			return err
 		}
 	}
 
@@ -387,12 +559,14 @@ func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, param
 	outputsPatcher := val.LookupPath(value.FieldPath(PatchOutputsFieldName))
 	if outputsPatcher.Exists() {
 		for _, auxiliary := range auxiliaries {
-			target := outputsPatcher.LookupPath(value.FieldPath(auxiliary.Name))
-			if !target.Exists() {
-				continue
+			targets, err := getOutputsPatches(outputsPatcher, auxiliary)
+			if err != nil {
+				return errors.WithMessagef(err, "trait=%s, invalid patchOutputs", td.name)
 			}
-			if err = auxiliary.Ins.Unify(target); err != nil {
-				return errors.WithMessagef(err, "trait=%s, to=%s, invalid patch trait into auxiliary workload", td.name, auxiliary.Name)
+			for _, target := range targets {
+				if err = auxiliary.Ins.Unify(target); err != nil {
+					return errors.WithMessagef(err, "trait=%s, to=%s, invalid patch trait into auxiliary workload", td.name, auxiliary.Name)
+				}
 			}
 		}
 	}
@@ -400,6 +574,74 @@ func (td *traitDef) Complete(ctx process.Context, abstractTemplate string, param
 	return nil
 }
 
+// outputsSelector is the match of an entry of the $selectors of patchOutputs, the patch of the entry is applied to
+// the auxiliaries matching all the given conditions
+type outputsSelector struct {
+	// Type the type of the auxiliary, i.e. the name of the trait generating it or AuxiliaryWorkload
+	Type       string            `json:"type,omitempty"`
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// getOutputsPatches returns the patches of patchOutputs targeting the auxiliary, i.e. the patch of its name, the
+// wildcard patch and the patches of the matched selectors
+func getOutputsPatches(outputsPatcher cue.Value, auxiliary process.Auxiliary) ([]cue.Value, error) {
+	var patches []cue.Value
+	if target := outputsPatcher.LookupPath(value.FieldPath(auxiliary.Name)); target.Exists() {
+		patches = append(patches, target)
+	}
+	if target := outputsPatcher.LookupPath(cue.MakePath(cue.Str(PatchOutputsWildcard))); target.Exists() {
+		patches = append(patches, target)
+	}
+	selectors := outputsPatcher.LookupPath(value.FieldPath(PatchOutputsSelectorsFieldName))
+	if !selectors.Exists() {
+		return patches, nil
+	}
+	iter, err := selectors.List()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid %s", PatchOutputsSelectorsFieldName)
+	}
+	obj := auxiliary.Ins.Value()
+	for iter.Next() {
+		var selector outputsSelector
+		if err := iter.Value().LookupPath(value.FieldPath("match")).Decode(&selector); err != nil {
+			return nil, errors.WithMessagef(err, "invalid %s", PatchOutputsSelectorsFieldName)
+		}
+		if !matchOutputsSelector(selector, auxiliary.Type, obj) {
+			continue
+		}
+		patches = append(patches, iter.Value().LookupPath(value.FieldPath("patch")))
+	}
+	return patches, nil
+}
+
+func matchOutputsSelector(match outputsSelector, auxiliaryType string, obj cue.Value) bool {
+	if match.Type != "" && match.Type != auxiliaryType {
+		return false
+	}
+	if match.APIVersion != "" {
+		if apiVersion, _ := obj.LookupPath(value.FieldPath("apiVersion")).String(); apiVersion != match.APIVersion {
+			return false
+		}
+	}
+	if match.Kind != "" {
+		if kind, _ := obj.LookupPath(value.FieldPath("kind")).String(); kind != match.Kind {
+			return false
+		}
+	}
+	if len(match.Labels) > 0 {
+		var labels map[string]string
+		_ = obj.LookupPath(value.FieldPath("metadata", "labels")).Decode(&labels)
+		for k, v := range match.Labels {
+			if labels[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func outputStatusBytes(ctx process.Context) []byte {
 	var statusBytes []byte
 	var outputMap map[string]interface{}
@@ -478,11 +720,14 @@ func GetBaseContextLabels(ctx process.Context) map[string]string {
 	return baseLabels
 }
 
-func initRoot(contextLabels map[string]string) map[string]interface{} {
+func initRoot(ctx process.Context, contextLabels map[string]string) map[string]interface{} {
 	var root = map[string]interface{}{}
 	for k, v := range contextLabels {
 		root[k] = v
 	}
+	if componentOutputs := ctx.GetData(velaprocess.ContextComponentOutputs); componentOutputs != nil {
+		root[velaprocess.ContextComponentOutputs] = componentOutputs
+	}
 	return root
 }
 
@@ -495,7 +740,7 @@ parameter: _
 
 func (td *traitDef) getTemplateContext(ctx process.Context, cli client.Reader, accessor util.NamespaceAccessor) (map[string]interface{}, error) {
 	baseLabels := GetBaseContextLabels(ctx)
-	var root = initRoot(baseLabels)
+	var root = initRoot(ctx, baseLabels)
 	var commonLabels = GetCommonLabels(baseLabels)
 	_, assists := ctx.Output()
 
@@ -509,7 +754,7 @@ func (td *traitDef) getTemplateContext(ctx process.Context, cli client.Reader, a
 			return nil, err
 		}
 		_ctx := withCluster(ctx.GetCtx(), traitRef)
-		object, err := getResourceFromObj(_ctx, ctx, traitRef, cli, accessor.For(traitRef), util.MergeMapOverrideWithDst(map[string]string{
+		object, err := td.getResource(_ctx, ctx, traitRef, cli, accessor.For(traitRef), util.MergeMapOverrideWithDst(map[string]string{
 			oam.TraitTypeLabel: assist.Type,
 		}, commonLabels), assist.Name)
 		if err != nil {
@@ -532,6 +777,77 @@ func (td *traitDef) GetTemplateContext(ctx process.Context, cli client.Client, a
 	return td.getTemplateContext(ctx, cli, accessor)
 }
 
+type policyDef struct {
+	def
+}
+
+// NewPolicyAbstractEngine create Policy Definition AbstractEngine
+func NewPolicyAbstractEngine(name string, opts ...EngineOption) AbstractEngine {
+	return &policyDef{
+		def: newDef(name, opts...),
+	}
+}
+
+// Complete do policy definition's rendering
+func (pd *policyDef) Complete(ctx process.Context, abstractTemplate string, params interface{}) error {
+	return pd.decorateError(completeBaseTemplate(ctx, "policy", pd.name, AuxiliaryPolicy, GetPolicyTemplateKey(pd.name), abstractTemplate, params), abstractTemplate)
+}
+
+func (pd *policyDef) getTemplateContext(ctx process.Context, cli client.Reader, accessor util.NamespaceAccessor) (map[string]interface{}, error) {
+	baseLabels := GetBaseContextLabels(ctx)
+	var root = initRoot(ctx, baseLabels)
+	var commonLabels = GetCommonLabels(baseLabels)
+
+	base, assists := ctx.Output()
+	policyOutput, err := base.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	// policy main resource will have the label("policydefinition.oam.dev/name"="name of policy") so that it is not
+	// mixed up with the workload of the component
+	_ctx := withCluster(ctx.GetCtx(), policyOutput)
+	object, err := pd.getResource(_ctx, ctx, policyOutput, cli, accessor.For(policyOutput), util.MergeMapOverrideWithDst(map[string]string{
+		oam.LabelPolicyDefinitionName: pd.name,
+	}, commonLabels), "")
+	if err != nil {
+		return nil, err
+	}
+	root[OutputFieldName] = object
+	outputs := make(map[string]interface{})
+	for _, assist := range assists {
+		if assist.Type != AuxiliaryPolicy {
+			continue
+		}
+		ref, err := assist.Ins.Unstructured()
+		if err != nil {
+			return nil, err
+		}
+		// AuxiliaryPolicy will have a unique label("trait.oam.dev/resource"="name of outputs") in per policy level
+		_ctx := withCluster(ctx.GetCtx(), ref)
+		object, err := pd.getResource(_ctx, ctx, ref, cli, accessor.For(ref), util.MergeMapOverrideWithDst(map[string]string{
+			oam.TraitTypeLabel:            AuxiliaryPolicy,
+			oam.LabelPolicyDefinitionName: pd.name,
+		}, commonLabels), assist.Name)
+		if err != nil {
+			return nil, err
+		}
+		outputs[assist.Name] = object
+	}
+	if len(outputs) > 0 {
+		root[OutputsFieldName] = outputs
+	}
+	return root, nil
+}
+
+// Status get policy status by customStatusTemplate
+func (pd *policyDef) Status(templateContext map[string]interface{}, request *health.StatusRequest) (*health.StatusResult, error) {
+	return health.GetStatus(templateContext, request)
+}
+
+func (pd *policyDef) GetTemplateContext(ctx process.Context, cli client.Client, accessor util.NamespaceAccessor) (map[string]interface{}, error) {
+	return pd.getTemplateContext(ctx, cli, accessor)
+}
+
 func getResourceFromObj(ctx context.Context, pctx process.Context, obj *unstructured.Unstructured, client client.Reader, namespace string, labels map[string]string, outputsResource string) (map[string]interface{}, error) {
 	if outputsResource != "" {
 		labels[oam.TraitResource] = outputsResource
@@ -563,69 +879,3 @@ func getResourceFromObj(ctx context.Context, pctx process.Context, obj *unstruct
 	}
 	return nil, errors.Errorf("no resources found gvk(%v) labels(%v)", obj.GroupVersionKind(), labels)
 }
-
-// FormatCUEError formats CUE errors in a user-friendly grouped format
-func FormatCUEError(err error, messagePrefix string, entityType, entityName string, val ...*cue.Value) error {
-	var allParamErrors = make(map[string]bool)
-	var allTemplateErrors = make(map[string]bool)
-
-	if err != nil {
-		errList := cueerrors.Errors(err)
-		for _, e := range errList {
-			errMsg := e.Error()
-			if strings.HasPrefix(errMsg, "parameter.") {
-				allParamErrors[errMsg] = true
-			} else {
-				allTemplateErrors[errMsg] = true
-			}
-		}
-
-		if len(val) > 0 && val[0] != nil {
-			if concreteErr := val[0].Validate(cue.Concrete(true)); concreteErr != nil {
-				concreteErrList := cueerrors.Errors(concreteErr)
-				for _, e := range concreteErrList {
-					errMsg := e.Error()
-					if strings.HasPrefix(errMsg, "parameter.") {
-						allParamErrors[errMsg] = true
-					} else {
-						allTemplateErrors[errMsg] = true
-					}
-				}
-			}
-		}
-	}
-
-	if len(allParamErrors) == 0 && len(allTemplateErrors) == 0 {
-		return nil
-	}
-
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("%s %s %s:", messagePrefix, entityType, entityName))
-
-	if len(allParamErrors) > 0 {
-		result.WriteString("\n\nParameter errors:\n")
-		// Sort errors for deterministic output
-		paramErrs := make([]string, 0, len(allParamErrors))
-		for errMsg := range allParamErrors {
-			paramErrs = append(paramErrs, errMsg)
-		}
-		sort.Strings(paramErrs)
-		for _, errMsg := range paramErrs {
-			result.WriteString("  " + errMsg + "\n")
-		}
-	}
-
-	if len(allTemplateErrors) > 0 {
-		result.WriteString("\n\nTemplate errors:\n")
-		templateErrs := make([]string, 0, len(allTemplateErrors))
-		for errMsg := range allTemplateErrors {
-			templateErrs = append(templateErrs, errMsg)
-		}
-		sort.Strings(templateErrs)
-		for _, errMsg := range templateErrs {
-			result.WriteString("  " + errMsg + "\n")
-		}
-	}
-
-	return fmt.Errorf("%s", strings.TrimRight(result.String(), "\n"))
-}
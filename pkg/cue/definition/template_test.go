@@ -20,19 +20,26 @@ import (
 	"strings"
 	"testing"
 
+	"cuelang.org/go/cue"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	wfprocess "github.com/kubevela/workflow/pkg/cue/process"
 
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/definition/health"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
 
@@ -1305,6 +1312,46 @@ outputs: abc :{
 				content: "name: \"test-abc\"\n",
 			}},
 		},
+		"struct-comprehension": {
+			template: `
+outputs: {
+	for _, n in ["zyx", "lmn", "abc"] {
+		"svc-\(n)": name: "test-\(n)"
+	}
+}
+`,
+			order: []struct {
+				name    string
+				content string
+			}{{
+				name:    "svc-zyx",
+				content: "name: \"test-zyx\"\n",
+			}, {
+				name:    "svc-lmn",
+				content: "name: \"test-lmn\"\n",
+			}, {
+				name:    "svc-abc",
+				content: "name: \"test-abc\"\n",
+			}},
+		},
+		"list-comprehension": {
+			template: `
+outputs: [for n in ["zyx", "lmn", "abc"] {name: "test-\(n)"}]
+`,
+			order: []struct {
+				name    string
+				content string
+			}{{
+				name:    "list-comprehension-0",
+				content: "name: \"test-zyx\"\n",
+			}, {
+				name:    "list-comprehension-1",
+				content: "name: \"test-lmn\"\n",
+			}, {
+				name:    "list-comprehension-2",
+				content: "name: \"test-abc\"\n",
+			}},
+		},
 	}
 	for k, v := range testcases {
 		td := NewTraitAbstractEngine(k)
@@ -1317,6 +1364,7 @@ outputs: abc :{
 		err := td.Complete(ctx, v.template, map[string]interface{}{})
 		assert.NoError(t, err)
 		_, assists := ctx.Output()
+		assert.Equal(t, len(v.order), len(assists))
 		for i, ss := range assists {
 			assert.Equal(t, ss.Name, v.order[i].name)
 			s, err := ss.Ins.String()
@@ -1326,6 +1374,69 @@ outputs: abc :{
 	}
 }
 
+func TestStableRenderOutputs(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.EnableStableRenderOutputs, true)
+	ctx := process.NewContext(process.ContextData{AppName: "myapp", CompName: "test", Namespace: "default"})
+	td := NewTraitAbstractEngine("svc")
+	require.NoError(t, td.Complete(ctx, `
+outputs: {
+	for _, n in ["zyx", "lmn", "abc"] {
+		"svc-\(n)": name: "test-\(n)"
+	}
+}
+`, nil))
+	_, auxiliaries := ctx.Output()
+	var names []string
+	for _, aux := range auxiliaries {
+		names = append(names, aux.Name)
+	}
+	require.Equal(t, []string{"svc-abc", "svc-lmn", "svc-zyx"}, names)
+}
+
+func TestOutputsGCPolicy(t *testing.T) {
+	ctx := process.NewContext(process.ContextData{AppName: "myapp", CompName: "test", Namespace: "default"})
+	td := NewTraitAbstractEngine("expose")
+	require.NoError(t, td.Complete(ctx, `
+outputs: service: {
+	$gc:        "orphan"
+	#port:      80
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: {
+		name: "svc"
+		annotations: a: "b"
+	}
+	spec: {
+		type: *"ClusterIP" | string
+		ports: [{port: #port}]
+	}
+}
+outputs: ingress: {
+	apiVersion: "networking.k8s.io/v1"
+	kind:       "Ingress"
+}
+patchOutputs: service: spec: type: "NodePort"
+`, nil))
+	_, auxiliaries := ctx.Output()
+	require.Len(t, auxiliaries, 2)
+	svc, err := auxiliaries[0].Ins.Unstructured()
+	require.NoError(t, err)
+	require.NotContains(t, svc.Object, GCFieldName)
+	require.Equal(t, map[string]string{"a": "b", oam.AnnotationGCPolicy: oam.GCPolicyOrphan}, svc.GetAnnotations())
+	require.Equal(t, "svc", svc.GetName())
+	svcType, _, _ := unstructured.NestedString(svc.Object, "spec", "type")
+	require.Equal(t, "NodePort", svcType)
+	// the definitions of the output are kept in its value
+	require.True(t, auxiliaries[0].Ins.Value().LookupPath(cue.ParsePath("#port")).Exists())
+	ingress, err := auxiliaries[1].Ins.Unstructured()
+	require.NoError(t, err)
+	require.Empty(t, ingress.GetAnnotations())
+
+	err = td.Complete(ctx, `outputs: service: {$gc: "unknown", apiVersion: "v1", kind: "Service"}`, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid $gc")
+}
+
 func TestTraitPatchSingleOutput(t *testing.T) {
 	baseTemplate := `
 	output: {
@@ -1380,6 +1491,73 @@ func TestTraitPatchSingleOutput(t *testing.T) {
 	r.Equal("val", val)
 }
 
+func TestTraitPatchOutputsWildcardAndSelectors(t *testing.T) {
+	baseTemplate := `
+	output: {
+		apiVersion: "apps/v1"
+		kind:       "Deployment"
+	}
+	outputs: web: {
+		apiVersion: "v1"
+		kind:       "Service"
+		metadata: labels: tier: "web"
+	}
+	outputs: db: {
+		apiVersion: "v1"
+		kind:       "Service"
+		metadata: labels: tier: "db"
+	}
+	outputs: config: {
+		apiVersion: "v1"
+		kind:       "ConfigMap"
+	}
+`
+	traitTemplate := `
+	patchOutputs: {
+		"*": metadata: annotations: patched: "true"
+		$selectors: [{
+			match: kind: "Service"
+			patch: spec: type: "NodePort"
+		}, {
+			match: {kind: "Service", labels: tier: "db"}
+			patch: spec: clusterIP: "None"
+		}, {
+			match: type: "other"
+			patch: metadata: annotations: other: "true"
+		}]
+		config: data: key: "val"
+	}
+`
+	ctx := process.NewContext(process.ContextData{
+		AppName:         "myapp",
+		CompName:        "test",
+		Namespace:       "default",
+		AppRevisionName: "myapp-v1",
+	})
+	r := require.New(t)
+	r.NoError(NewWorkloadAbstractEngine("test").Complete(ctx, baseTemplate, nil))
+	r.NoError(NewTraitAbstractEngine("patch").Complete(ctx, traitTemplate, nil))
+	_, assists := ctx.Output()
+	r.Equal(3, len(assists))
+	objs := map[string]*unstructured.Unstructured{}
+	for _, assist := range assists {
+		obj, err := assist.Ins.Unstructured()
+		r.NoError(err)
+		r.Equal(map[string]string{"patched": "true"}, obj.GetAnnotations())
+		objs[assist.Name] = obj
+	}
+	svcType, _, _ := unstructured.NestedString(objs["web"].Object, "spec", "type")
+	r.Equal("NodePort", svcType)
+	_, found, _ := unstructured.NestedString(objs["web"].Object, "spec", "clusterIP")
+	r.False(found)
+	clusterIP, _, _ := unstructured.NestedString(objs["db"].Object, "spec", "clusterIP")
+	r.Equal("None", clusterIP)
+	_, found, _ = unstructured.NestedMap(objs["config"].Object, "spec")
+	r.False(found)
+	data, _, _ := unstructured.NestedString(objs["config"].Object, "data", "key")
+	r.Equal("val", data)
+}
+
 func TestTraitCompleteErrorCases(t *testing.T) {
 	cases := map[string]struct {
 		ctx       wfprocess.Context
@@ -1461,7 +1639,7 @@ outputs: service: {
 				"protocol": "INVALID",
 			},
 			isWorkload: false,
-			wantErr:    "validation failed for trait my-trait:\n\nParameter errors:\n  parameter.port: invalid value 70000 (out of bound <=65535)\n  parameter.protocol: 2 errors in empty disjunction:\n  parameter.protocol: conflicting values \"TCP\" and \"INVALID\"\n  parameter.protocol: conflicting values \"UDP\" and \"INVALID\"",
+			wantErr:    "validation failed for trait my-trait:\n\nParameter errors:\n  parameter.port: invalid value 70000 (out of bound <=65535)\n  parameter.protocol: 2 errors in empty disjunction:\n    ├─ conflicting values \"TCP\" and \"INVALID\"\n    └─ conflicting values \"UDP\" and \"INVALID\"",
 		},
 		"mixed parameter and template errors": {
 			name: "test-workload",
@@ -1736,6 +1914,198 @@ outputs: myconfig: {
 	}
 }
 
+func TestOfflineGetTemplateContext(t *testing.T) {
+	ctx := process.NewContext(process.ContextData{
+		AppName:         "myapp",
+		CompName:        "test",
+		Namespace:       "default",
+		AppRevisionName: "myapp-v1",
+	})
+	wt := NewWorkloadAbstractEngine("test", WithOfflineRender())
+	require.NoError(t, wt.Complete(ctx, `
+output: {
+	apiVersion: "apps/v1"
+	kind: "Deployment"
+	metadata: name: context.name
+}
+outputs: service: {
+	apiVersion: "v1"
+	kind: "Service"
+	metadata: name: context.name
+}
+`, nil))
+	tt := NewTraitAbstractEngine("expose", WithOfflineRender())
+	require.NoError(t, tt.Complete(ctx, `
+outputs: ingress: {
+	apiVersion: "networking.k8s.io/v1"
+	kind: "Ingress"
+	metadata: name: context.name
+}
+`, nil))
+
+	accessor := util.NewApplicationResourceNamespaceAccessor("default", "")
+	templateContext, err := wt.GetTemplateContext(ctx, nil, accessor)
+	require.NoError(t, err)
+	output := templateContext[OutputFieldName].(map[string]interface{})
+	require.Equal(t, "Deployment", output["kind"])
+	require.Equal(t, "default", output["metadata"].(map[string]interface{})["namespace"])
+	svc := templateContext[OutputsFieldName].(map[string]interface{})["service"].(map[string]interface{})
+	require.Equal(t, "service", svc["metadata"].(map[string]interface{})["labels"].(map[string]interface{})["trait.oam.dev/resource"])
+
+	templateContext, err = tt.GetTemplateContext(ctx, nil, accessor)
+	require.NoError(t, err)
+	require.Contains(t, templateContext[OutputsFieldName], "ingress")
+	require.NotContains(t, templateContext[OutputsFieldName], "service")
+}
+
+func TestTemplateContextComponentOutputs(t *testing.T) {
+	componentOutputs := map[string]interface{}{
+		"backend": map[string]interface{}{"status": map[string]interface{}{"healthy": true}},
+	}
+	ctx := process.NewContext(process.ContextData{
+		AppName:          "myapp",
+		CompName:         "test",
+		Namespace:        "default",
+		AppRevisionName:  "myapp-v1",
+		ComponentOutputs: componentOutputs,
+	})
+	wt := NewWorkloadAbstractEngine("test", WithOfflineRender())
+	require.NoError(t, wt.Complete(ctx, `
+output: {
+	apiVersion: "v1"
+	kind: "ConfigMap"
+	data: healthy: "\(context.componentOutputs.backend.status.healthy)"
+}
+`, nil))
+	templateContext, err := wt.GetTemplateContext(ctx, nil, util.NewApplicationResourceNamespaceAccessor("default", ""))
+	require.NoError(t, err)
+	require.Equal(t, componentOutputs, templateContext[process.ContextComponentOutputs])
+	output := templateContext[OutputFieldName].(map[string]interface{})
+	require.Equal(t, "true", output["data"].(map[string]interface{})["healthy"])
+}
+
+func TestPolicyTemplateComplete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	policyTemplate := `
+output: {
+	apiVersion: "v1"
+	kind: "ConfigMap"
+	metadata: {
+		name: parameter.name
+		namespace: "default"
+	}
+}
+outputs: secret: {
+	apiVersion: "v1"
+	kind: "Secret"
+	metadata: {
+		name: parameter.name + "-secret"
+		namespace: "default"
+	}
+}
+parameter: name: string
+`
+	ctx := process.NewContext(process.ContextData{
+		AppName:         "myapp",
+		CompName:        "my-policy",
+		Namespace:       "default",
+		AppRevisionName: "myapp-v1",
+	})
+	pd := NewPolicyAbstractEngine("my-policy")
+	require.NoError(t, pd.Complete(ctx, policyTemplate, map[string]interface{}{"name": "cfg"}))
+
+	base, assists := ctx.Output()
+	output, err := base.Unstructured()
+	require.NoError(t, err)
+	require.Equal(t, "cfg", output.GetName())
+	require.Len(t, assists, 1)
+	require.Equal(t, AuxiliaryPolicy, assists[0].Type)
+	require.Equal(t, "secret", assists[0].Name)
+
+	err = pd.Complete(process.NewContext(process.ContextData{}), policyTemplate, map[string]interface{}{"name": 1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validation failed for policy my-policy:")
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "default"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cfg-secret", Namespace: "default"}},
+	).Build()
+	templateContext, err := pd.GetTemplateContext(ctx, cli, util.NewApplicationResourceNamespaceAccessor("default", ""))
+	require.NoError(t, err)
+	require.Equal(t, "cfg", templateContext[OutputFieldName].(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+	require.Contains(t, templateContext[OutputsFieldName], "secret")
+
+	status, err := pd.Status(templateContext, &health.StatusRequest{Health: "isHealth: context.output.metadata.name == \"cfg\""})
+	require.NoError(t, err)
+	require.True(t, status.Healthy)
+}
+
+func TestPolicyGetTemplateContext(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	policyTemplate := `
+output: {
+	apiVersion: "v1"
+	kind: "ConfigMap"
+	metadata: name: "cfg"
+}
+outputs: token: {
+	apiVersion: "v1"
+	kind: "Secret"
+}
+`
+	newCtx := func() wfprocess.Context {
+		return process.NewContext(process.ContextData{
+			AppName:         "myapp",
+			CompName:        "my-policy",
+			Namespace:       "default",
+			AppRevisionName: "myapp-v1",
+		})
+	}
+	commonLabels := map[string]string{
+		oam.LabelAppName:      "myapp",
+		oam.LabelAppComponent: "my-policy",
+		oam.LabelAppRevision:  "myapp-v1",
+	}
+	withLabels := func(labels map[string]string) map[string]string {
+		return util.MergeMapOverrideWithDst(labels, commonLabels)
+	}
+
+	ctx := newCtx()
+	pd := NewPolicyAbstractEngine("my-policy")
+	require.NoError(t, pd.Complete(ctx, policyTemplate, nil))
+	require.NotNil(t, ctx.GetData(GetPolicyTemplateKey("my-policy")))
+	require.Nil(t, ctx.GetData(GetWorkloadTemplateKey("my-policy")))
+
+	// the secret of the workload shares the common labels, only the one labeled as the policy outputs is picked
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "default"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "workload-token", Namespace: "default",
+			Labels: withLabels(map[string]string{oam.TraitTypeLabel: AuxiliaryWorkload, oam.TraitResource: "token"})}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "policy-token", Namespace: "default",
+			Labels: withLabels(map[string]string{oam.TraitTypeLabel: AuxiliaryPolicy, oam.TraitResource: "token", oam.LabelPolicyDefinitionName: "my-policy"})}},
+	).Build()
+	templateContext, err := pd.GetTemplateContext(ctx, cli, util.NewApplicationResourceNamespaceAccessor("default", ""))
+	require.NoError(t, err)
+	token := templateContext[OutputsFieldName].(map[string]interface{})["token"].(map[string]interface{})
+	require.Equal(t, "policy-token", token["metadata"].(map[string]interface{})["name"])
+
+	ctx = newCtx()
+	pd = NewPolicyAbstractEngine("my-policy", WithOfflineRender())
+	require.NoError(t, pd.Complete(ctx, policyTemplate, nil))
+	templateContext, err = pd.GetTemplateContext(ctx, nil, util.NewApplicationResourceNamespaceAccessor("default", ""))
+	require.NoError(t, err)
+	output := templateContext[OutputFieldName].(map[string]interface{})
+	require.Equal(t, "my-policy", output["metadata"].(map[string]interface{})["labels"].(map[string]interface{})[oam.LabelPolicyDefinitionName])
+	token = templateContext[OutputsFieldName].(map[string]interface{})["token"].(map[string]interface{})
+	labels := token["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+	require.Equal(t, AuxiliaryPolicy, labels[oam.TraitTypeLabel])
+	require.Equal(t, "token", labels[oam.TraitResource])
+}
+
 func TestGetCommonLabels(t *testing.T) {
 	type want struct {
 		labels map[string]string
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/kubevela/pkg/cue/cuex"
+	"github.com/pkg/errors"
+)
+
+var (
+	// RenderTimeout the max duration the rendering of a definition template including the calls of its providers is
+	// waited for, the rendering fails with an error once exceeded. 0 means waiting until the rendering finishes.
+	RenderTimeout time.Duration
+	// MaxConcurrentRenders the max number of definition templates evaluated at the same time when the RenderTimeout
+	// is set. The evaluations given up on are not counted, see compileString. 0 means no limit.
+	MaxConcurrentRenders = 64
+)
+
+// renderSlot is taken by an evaluation of the template of the key
+type renderSlot struct {
+	key       string
+	abandoned bool
+	finished  bool
+}
+
+// renderLimiter bounds the number of the evaluations in progress, and accounts the evaluations given up on by the
+// hash of their templates
+type renderLimiter struct {
+	mu        sync.Mutex
+	running   int
+	abandoned map[string]int
+	// released is closed and replaced whenever a slot is released
+	released chan struct{}
+}
+
+var defaultRenderLimiter = newRenderLimiter()
+
+func newRenderLimiter() *renderLimiter {
+	return &renderLimiter{abandoned: map[string]int{}, released: make(chan struct{})}
+}
+
+// acquire waits until less than limit evaluations are in progress. The template of the key is refused while an
+// evaluation of it given up on is still running.
+func (l *renderLimiter) acquire(ctx context.Context, key string, limit int) (*renderSlot, error) {
+	for {
+		l.mu.Lock()
+		if l.abandoned[key] > 0 {
+			l.mu.Unlock()
+			return nil, errors.New("the previous rendering of the template did not finish within the timeout and is still running")
+		}
+		if limit <= 0 || l.running < limit {
+			l.running++
+			l.mu.Unlock()
+			return &renderSlot{key: key}, nil
+		}
+		released := l.released
+		l.mu.Unlock()
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, errors.Wrapf(ctx.Err(), "too many templates are being rendered, at most %d renderings run at the same time", limit)
+		}
+	}
+}
+
+// abandon releases the slot of the evaluation given up on, which is accounted by its template until it finishes
+func (l *renderLimiter) abandon(slot *renderSlot) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if slot.finished || slot.abandoned {
+		return
+	}
+	slot.abandoned = true
+	l.abandoned[slot.key]++
+	l.releaseLocked()
+}
+
+// finish is called once the evaluation of the slot ends
+func (l *renderLimiter) finish(slot *renderSlot) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot.finished = true
+	if !slot.abandoned {
+		l.releaseLocked()
+		return
+	}
+	if l.abandoned[slot.key]--; l.abandoned[slot.key] <= 0 {
+		delete(l.abandoned, slot.key)
+	}
+}
+
+func (l *renderLimiter) releaseLocked() {
+	l.running--
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+func (l *renderLimiter) inProgress() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running
+}
+
+func (l *renderLimiter) abandonedRenders() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, c := range l.abandoned {
+		n += c
+	}
+	return n
+}
+
+// compileString compiles and evaluates the content with the compiler, waiting for at most the RenderTimeout. The
+// context passed to the providers is cancelled once the timeout is exceeded, while the evaluation of CUE itself
+// cannot be interrupted: it is given up on and keeps running in the background so that the reconcile worker is
+// released. Such an evaluation gives back its slot of MaxConcurrentRenders, so that a few runaway templates don't
+// starve the healthy ones, and its template, identified by the key, is not rendered again until it finishes, so
+// that a runaway template keeps at most one goroutine.
+func compileString(ctx context.Context, compiler *cuex.Compiler, key, content string) (cue.Value, error) {
+	timeout := RenderTimeout
+	if timeout <= 0 {
+		return compiler.CompileString(ctx, content)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	slot, err := defaultRenderLimiter.acquire(ctx, key, MaxConcurrentRenders)
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	type result struct {
+		val cue.Value
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		defer defaultRenderLimiter.finish(slot)
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- result{err: fmt.Errorf("panic when rendering the template: %v", r)}
+			}
+		}()
+		val, err := compiler.CompileString(ctx, content)
+		if err == nil {
+			// evaluate the value completely, so that the validation and the lookups of the outputs afterwards reuse
+			// the evaluated result instead of evaluating it beyond the timeout, the errors are reported by them
+			_ = val.Validate()
+		}
+		ch <- result{val: val, err: err}
+	}()
+	select {
+	case r := <-ch:
+		// the providers may fail because of the cancelled context before the timeout is observed here
+		if r.err != nil && ctx.Err() != nil {
+			return cue.Value{}, renderAbortedError(ctx, timeout)
+		}
+		return r.val, r.err
+	case <-ctx.Done():
+		defaultRenderLimiter.abandon(slot)
+		return cue.Value{}, renderAbortedError(ctx, timeout)
+	}
+}
+
+func renderAbortedError(ctx context.Context, timeout time.Duration) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Errorf("rendering the template did not finish within %s and is given up on, "+
+			"check the template for unbounded comprehensions or slow providers", timeout)
+	}
+	return errors.Wrap(ctx.Err(), "rendering the template is aborted")
+}
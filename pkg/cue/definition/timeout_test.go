@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+func TestRenderTimeout(t *testing.T) {
+	origTimeout := RenderTimeout
+	t.Cleanup(func() { RenderTimeout = origTimeout })
+
+	hanging, err := cuexruntime.NewInternalPackage("org/echo", `
+package echo
+#Echo: {
+	#do:       "echo"
+	#provider: "org/echo"
+	input:   string
+	output?: string
+}
+`, map[string]cuexruntime.ProviderFn{
+		"echo": cuexruntime.GenericProviderFn[echoVars, map[string]string](func(ctx context.Context, _ *echoVars) (*map[string]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+	})
+	require.NoError(t, err)
+
+	template := `
+import "vela/org/echo"
+
+echoed: echo.#Echo & {input: "val"}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: echoed.output
+}
+`
+	render := func() error {
+		ctx := WithMockProviders(context.Background(), hanging)
+		pCtx := process.NewContext(process.ContextData{Ctx: ctx, AppName: "app", CompName: "comp", Namespace: "default"})
+		return NewWorkloadAbstractEngine("comp").Complete(pCtx, template, nil)
+	}
+
+	RenderTimeout = 100 * time.Millisecond
+	start := time.Now()
+	err = render()
+	require.ErrorContains(t, err, "rendering the template did not finish within 100ms")
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// the evaluation given up on ends once the provider observes the cancellation
+	require.Eventually(t, func() bool { return defaultRenderLimiter.abandonedRenders() == 0 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 0, defaultRenderLimiter.inProgress())
+
+	// the renderings finishing in time are not affected
+	ctx := process.NewContext(process.ContextData{AppName: "app", CompName: "comp", Namespace: "default"})
+	require.NoError(t, NewWorkloadAbstractEngine("comp").Complete(ctx, `output: {apiVersion: "v1", kind: "ConfigMap"}`, nil))
+	require.Equal(t, 0, defaultRenderLimiter.inProgress())
+}
+
+func TestMaxConcurrentRenders(t *testing.T) {
+	origTimeout, origMax := RenderTimeout, MaxConcurrentRenders
+	t.Cleanup(func() { RenderTimeout, MaxConcurrentRenders = origTimeout, origMax })
+
+	unblock := make(chan struct{})
+	// the provider ignores the cancellation, so the evaluation keeps running after the timeout
+	stuck, err := cuexruntime.NewInternalPackage("org/echo", `
+package echo
+#Echo: {
+	#do:       "echo"
+	#provider: "org/echo"
+	input:   string
+	output?: string
+}
+`, map[string]cuexruntime.ProviderFn{
+		"echo": cuexruntime.GenericProviderFn[echoVars, map[string]string](func(_ context.Context, in *echoVars) (*map[string]string, error) {
+			<-unblock
+			return &map[string]string{"output": in.Input}, nil
+		}),
+	})
+	require.NoError(t, err)
+	template := `
+import "vela/org/echo"
+
+echoed: echo.#Echo & {input: "val"}
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	data: name: echoed.output
+}
+`
+	mockCtx := WithMockProviders(context.Background(), stuck)
+	render := func(template string) error {
+		pCtx := process.NewContext(process.ContextData{Ctx: mockCtx, AppName: "app", CompName: "comp", Namespace: "default"})
+		return NewWorkloadAbstractEngine("comp").Complete(pCtx, template, nil)
+	}
+
+	RenderTimeout, MaxConcurrentRenders = 100*time.Millisecond, 1
+	require.ErrorContains(t, render(template), "did not finish within 100ms")
+	require.Equal(t, 1, defaultRenderLimiter.abandonedRenders())
+
+	// the stuck evaluation gives back its slot, while its template is refused until it finishes
+	require.Equal(t, 0, defaultRenderLimiter.inProgress())
+	require.NoError(t, render(`output: {apiVersion: "v1", kind: "ConfigMap"}`))
+	require.ErrorContains(t, render(template), "the previous rendering of the template did not finish")
+	require.Equal(t, 1, defaultRenderLimiter.abandonedRenders())
+
+	// the goroutine of the stuck evaluation ends once the provider returns
+	close(unblock)
+	require.Eventually(t, func() bool { return defaultRenderLimiter.abandonedRenders() == 0 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, render(template))
+	require.Equal(t, 0, defaultRenderLimiter.inProgress())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue"
+	cueerrors "cuelang.org/go/cue/errors"
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+	"github.com/pkg/errors"
+
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+const (
+	validateAppName   = "validate-app"
+	validateNamespace = "default"
+)
+
+// ValidateTemplate compiles the template of a definition against a synthetic context and the sample parameters
+// without touching the cluster. It returns a *CueValidationError if the template does not compile, fails the
+// validation or reports errors through `errs`. When params is not nil, the sample parameters must also satisfy
+// the parameter schema completely, so that missing required parameters are reported.
+func ValidateTemplate(ctx context.Context, entityType, entityName, template string, params interface{}) error {
+	val, err := compileSample(ctx, entityType, entityName, template, params)
+	if err != nil {
+		return err
+	}
+
+	var userErrors []string
+	if errs := val.LookupPath(value.FieldPath(ErrsFieldName)); errs.Exists() {
+		// the errs field fails to decode if it depends on invalid values, which are reported by the validation
+		userErrors, _, _ = decodeUserErrors(errs)
+	}
+
+	validationErr := val.Validate()
+	if params != nil {
+		paramErr := val.LookupPath(value.FieldPath(velaprocess.ParameterFieldName)).Validate(cue.Concrete(true))
+		switch {
+		case validationErr == nil:
+			validationErr = paramErr
+		case paramErr != nil:
+			validationErr = cueerrors.Append(cueerrors.Promote(validationErr, ""), cueerrors.Promote(paramErr, ""))
+		}
+	}
+	if validationErr == nil && len(userErrors) == 0 {
+		return nil
+	}
+	return def{}.decorateError(NewCueValidationError(validationErr, "validation failed for", entityType, entityName, userErrors), template)
+}
+
+// ValidateParameters validates the parameters of an application against the parameter schema of the template
+// without rendering the outputs, it is intended for the validating webhooks to reject invalid parameters at
+// admission instead of failing during the reconciliation. The required parameters are not enforced as they might
+// be supplied later, e.g. by workflow inputs or override policies. It returns a *CueValidationError carrying the
+// same message as the one reported by the rendering.
+func ValidateParameters(ctx context.Context, entityType, entityName, template string, params interface{}) error {
+	val, err := compileSample(ctx, entityType, entityName, template, params)
+	if err != nil {
+		return err
+	}
+	return ValidateParameterValue(val, entityType, entityName, template)
+}
+
+// ValidateParameterValue validates the parameters in the value compiled from the template like ValidateParameters,
+// it is intended for the callers having compiled the template with the real process context.
+func ValidateParameterValue(val cue.Value, entityType, entityName, template string) error {
+	paramErr := val.LookupPath(value.FieldPath(velaprocess.ParameterFieldName)).Validate(cue.Concrete(false))
+	if paramErr == nil {
+		return nil
+	}
+	return def{}.decorateError(NewCueValidationError(paramErr, "validation failed for", entityType, entityName, nil), template)
+}
+
+// compileSample compiles the template with the parameters against a synthetic process context
+func compileSample(ctx context.Context, entityType, entityName, template string, params interface{}) (cue.Value, error) {
+	var paramFile = velaprocess.ParameterFieldName + ": {}"
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return cue.Value{}, errors.WithMessagef(err, "marshal parameter of %s %s", entityType, entityName)
+		}
+		if string(bt) != "null" {
+			paramFile = fmt.Sprintf("%s: %s", velaprocess.ParameterFieldName, string(bt))
+		}
+	}
+
+	pctx := velaprocess.NewContext(velaprocess.ContextData{
+		Ctx:             ctx,
+		AppName:         validateAppName,
+		CompName:        entityName,
+		Namespace:       validateNamespace,
+		AppRevisionName: validateAppName + "-v1",
+	})
+	c, err := pctx.BaseContextFile()
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	val, err := compileTemplate(ctx, renderTemplate(template), paramFile, c)
+	if err != nil {
+		return cue.Value{}, def{}.decorateError(NewCueValidationError(err, "invalid template of", entityType, entityName, nil), template)
+	}
+	return val, nil
+}
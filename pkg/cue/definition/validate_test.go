@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	template := `
+parameter: {
+	image:    string
+	replicas: *1 | int & >=1
+}
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: context.name
+	spec: {
+		replicas: parameter.replicas
+		template: spec: containers: [{image: parameter.image}]
+	}
+}
+errs: [if parameter.replicas > 10 {"too many replicas"}]
+`
+	ctx := context.Background()
+	require.NoError(t, ValidateTemplate(ctx, "component", "worker", template, nil))
+	require.NoError(t, ValidateTemplate(ctx, "component", "worker", template, map[string]interface{}{"image": "nginx"}))
+
+	testCases := map[string]struct {
+		template       string
+		params         map[string]interface{}
+		userErrors     []string
+		parameterError ErrorCode
+		templateErrors bool
+	}{
+		"missing required parameter": {
+			template:       template,
+			params:         map[string]interface{}{"replicas": 2},
+			parameterError: ErrorCodeIncompleteValue,
+		},
+		"out of bound parameter": {
+			template:       template,
+			params:         map[string]interface{}{"image": "nginx", "replicas": 0},
+			parameterError: ErrorCodeOutOfBound,
+		},
+		"user errors": {
+			template:   template,
+			params:     map[string]interface{}{"image": "nginx", "replicas": 11},
+			userErrors: []string{"too many replicas"},
+		},
+		"broken template": {
+			template:       `output: {kind: "A"} & {kind: "B"}`,
+			templateErrors: true,
+		},
+		"syntax error": {
+			template:       `output: {`,
+			templateErrors: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTemplate(ctx, "component", "worker", tc.template, tc.params)
+			require.Error(t, err)
+			var verr *CueValidationError
+			require.True(t, errors.As(err, &verr))
+			require.Equal(t, "worker", verr.EntityName)
+			require.Equal(t, tc.userErrors, verr.UserErrors)
+			var codes []ErrorCode
+			for _, e := range verr.ParameterErrors {
+				codes = append(codes, e.Code)
+			}
+			if tc.parameterError == "" {
+				require.Empty(t, codes)
+			} else {
+				require.Contains(t, codes, tc.parameterError)
+			}
+			require.Equal(t, tc.templateErrors, len(verr.TemplateErrors) > 0)
+		})
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	template := `
+parameter: {
+	image:    string
+	port:     int & >=1 & <=65535
+	protocol: *"TCP" | "UDP"
+}
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: context.outputs.missing.name
+}
+`
+	ctx := context.Background()
+	// the missing required parameters and the outputs are not validated
+	require.NoError(t, ValidateParameters(ctx, "workload", "webservice", template, map[string]interface{}{"port": 80}))
+
+	err := ValidateParameters(ctx, "workload", "webservice", template, map[string]interface{}{"port": 0, "protocol": "HTTP"})
+	var verr *CueValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, []string{"parameter.port", "parameter.protocol"}, verr.ParameterPaths(0))
+	require.Empty(t, verr.TemplateErrors)
+	require.Contains(t, err.Error(), "validation failed for workload webservice:\n\nParameter errors:\n")
+	require.Equal(t, ErrorCodeOutOfBound, verr.ParameterErrors[0].Code)
+	require.Equal(t, 4, verr.ParameterErrors[0].Line)
+
+	err = ValidateParameters(ctx, "workload", "webservice", `parameter: {`, nil)
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "invalid template of", verr.Prefix)
+}
@@ -51,6 +51,8 @@ type ContextData struct {
 
 	ClusterVersion types.ClusterVersion
 	Output         interface{}
+	// ComponentOutputs the rendered results of the sibling components, see ContextComponentOutputs
+	ComponentOutputs map[string]interface{}
 }
 
 // NewContext creates a new process context
@@ -79,6 +81,9 @@ func NewContext(data ContextData) process.Context {
 	if data.Output != nil {
 		ctx.PushData(OutputFieldName, data.Output)
 	}
+	if len(data.ComponentOutputs) > 0 {
+		ctx.PushData(ContextComponentOutputs, data.ComponentOutputs)
+	}
 	return ctx
 }
 
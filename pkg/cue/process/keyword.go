@@ -51,6 +51,8 @@ const (
 	ContextCompRevisionName = "revision"
 	// ContextComponents is the components of app
 	ContextComponents = "components"
+	// ContextComponentOutputs is the rendered output, outputs and status of the sibling components, indexed by name
+	ContextComponentOutputs = "componentOutputs"
 	// ContextComponentType is the component type of current trait binding with
 	ContextComponentType = "componentType"
 	// ContextDataArtifacts is used to store unstructured resources of components
@@ -123,6 +123,19 @@ const (
 	// ValidateResourcesExist enables webhook validation to check if resource types referenced in
 	// ComponentDefinition/TraitDefinition/WorkflowStepDefinition/PolicyDefinition CUE templates exist in the cluster
 	ValidateResourcesExist = "ValidateResourcesExist"
+
+	// EnableParallelTraitRendering compile the traits of a component concurrently when they declare no rendering
+	// schedule and don't refer to context.output(s). The patches are still applied in order.
+	EnableParallelTraitRendering = "EnableParallelTraitRendering"
+
+	// EnableStableRenderOutputs sort the outputs of definitions by name instead of the CUE field order and annotate
+	// each rendered resource with the hash of its content, so that the unchanged renders can be detected.
+	EnableStableRenderOutputs = "EnableStableRenderOutputs"
+
+	// EnableValidationErrorCache reuse the validation error of a definition rendered repeatedly with the same template
+	// and parameters for a while, so that an application reconciling with invalid parameters doesn't extract and log
+	// the same errors on every reconcile.
+	EnableValidationErrorCache = "EnableValidationErrorCache"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -151,6 +164,9 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	EnableCueValidation:                           {Default: false, PreRelease: featuregate.Beta},
 	EnableApplicationStatusMetrics:                {Default: false, PreRelease: featuregate.Alpha},
 	ValidateResourcesExist:                        {Default: false, PreRelease: featuregate.Alpha},
+	EnableParallelTraitRendering:                  {Default: false, PreRelease: featuregate.Alpha},
+	EnableStableRenderOutputs:                     {Default: false, PreRelease: featuregate.Alpha},
+	EnableValidationErrorCache:                    {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
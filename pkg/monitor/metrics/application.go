@@ -89,6 +89,14 @@ var (
 	}, []string{"app_name", "namespace"})
 )
 
+var (
+	// ValidationErrorCacheCounter report the hit/miss of the definition validation error cache
+	ValidationErrorCacheCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubevela_definition_validation_error_cache_total",
+		Help: "definition validation error cache lookups.",
+	}, []string{"result"})
+)
+
 var (
 	// ListResourceTrackerCounter report the list resource tracker number.
 	ListResourceTrackerCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -54,6 +54,7 @@ var collectorGroup = []prometheus.Collector{
 	ClusterPodAllocatableGauge,
 	ClusterMemoryUsageGauge,
 	ClusterCPUUsageGauge,
+	ValidationErrorCacheCounter,
 }
 
 var (
@@ -198,6 +198,13 @@ const (
 	// AnnotationResourceURL records the source url of the Kubernetes object
 	AnnotationResourceURL = "app.oam.dev/resource-url"
 
+	// AnnotationGCPolicy records the garbage collection policy declared by the definition for the resource,
+	// one of GCPolicyOrphan, GCPolicyCascade and GCPolicyNever
+	AnnotationGCPolicy = "app.oam.dev/gc-policy"
+
+	// AnnotationContentHash records the hash of the rendered content of the resource, excluding the annotation itself
+	AnnotationContentHash = "app.oam.dev/content-hash"
+
 	// AnnotationIgnoreWithoutCompKey indicates the bond component.
 	// Deprecated: please use AnnotationAddonDefinitionBindCompKey.
 	AnnotationIgnoreWithoutCompKey = "addon.oam.dev/ignore-without-component"
@@ -209,6 +216,15 @@ const (
 	AnnotationSkipResume = "controller.core.oam.dev/skip-resume"
 )
 
+const (
+	// GCPolicyOrphan the resource is left in the cluster when it is recycled
+	GCPolicyOrphan = "orphan"
+	// GCPolicyCascade the resource is deleted together with its dependents in background when it is recycled
+	GCPolicyCascade = "cascade"
+	// GCPolicyNever the resource is never recycled
+	GCPolicyNever = "never"
+)
+
 const (
 	// ResourceTopologyFormatYAML mark the format of resource topology is yaml, by default, it's yaml.
 	ResourceTopologyFormatYAML = "yaml"
@@ -56,10 +56,8 @@ func (h *resourceKeeper) Delete(ctx context.Context, manifests []*unstructured.U
 	for _, manifest := range manifests {
 		if manifest != nil {
 			_options := options
-			if h.garbageCollectPolicy != nil {
-				if strategy := h.garbageCollectPolicy.FindStrategy(manifest); strategy != nil {
-					_options = append(_options, GarbageCollectStrategyOption(*strategy))
-				}
+			if strategy := h.findGarbageCollectStrategy(manifest); strategy != nil {
+				_options = append(_options, GarbageCollectStrategyOption(*strategy))
 			}
 			cfg := newDeleteConfig(_options...)
 			if err = h.delete(ctx, manifest, cfg); err != nil {
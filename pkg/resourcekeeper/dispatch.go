@@ -98,10 +98,8 @@ func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.U
 	for _, manifest := range manifests {
 		if manifest != nil {
 			_options := options
-			if h.garbageCollectPolicy != nil {
-				if strategy := h.garbageCollectPolicy.FindStrategy(manifest); strategy != nil {
-					_options = append(_options, GarbageCollectStrategyOption(*strategy))
-				}
+			if strategy := h.findGarbageCollectStrategy(manifest); strategy != nil {
+				_options = append(_options, GarbageCollectStrategyOption(*strategy))
 			}
 			cfg := newDispatchConfig(_options...)
 			switch {
@@ -405,6 +405,9 @@ func DeleteManagedResourceInApplication(ctx context.Context, cli client.Client,
 	if garbageCollectPolicy, _ := policy.ParsePolicy[v1alpha1.GarbageCollectPolicySpec](app); garbageCollectPolicy != nil {
 		isOrphan, opts = garbageCollectPolicy.FindDeleteOption(obj)
 	}
+	if !isOrphan && len(opts) == 0 {
+		isOrphan, opts = findDeleteOption(obj)
+	}
 
 	if mr.SkipGC || hasOrphanFinalizer(app) || isOrphan {
 		if labels := obj.GetLabels(); labels != nil {
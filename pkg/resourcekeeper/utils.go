@@ -17,8 +17,10 @@ limitations under the License.
 package resourcekeeper
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
@@ -63,6 +65,32 @@ func (h *resourceKeeper) getUpdateStrategy(manifest *unstructured.Unstructured)
 	return h.resourceUpdatePolicy.FindStrategy(manifest)
 }
 
+// findGarbageCollectStrategy finds the gc strategy of the resource, the garbage-collect policy of the application
+// takes precedence over the gc-policy annotation declared by the definition
+func (h *resourceKeeper) findGarbageCollectStrategy(manifest *unstructured.Unstructured) *v1alpha1.GarbageCollectStrategy {
+	if h.garbageCollectPolicy != nil {
+		if strategy := h.garbageCollectPolicy.FindStrategy(manifest); strategy != nil {
+			return strategy
+		}
+	}
+	if manifest.GetAnnotations()[oam.AnnotationGCPolicy] == oam.GCPolicyNever {
+		strategy := v1alpha1.GarbageCollectStrategyNever
+		return &strategy
+	}
+	return nil
+}
+
+// findDeleteOption finds the delete option of the resource from the gc-policy annotation declared by the definition
+func findDeleteOption(obj *unstructured.Unstructured) (bool, []client.DeleteOption) {
+	switch obj.GetAnnotations()[oam.AnnotationGCPolicy] {
+	case oam.GCPolicyOrphan:
+		return true, []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationOrphan)}
+	case oam.GCPolicyCascade:
+		return false, []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationBackground)}
+	}
+	return false, nil
+}
+
 // hasOrphanFinalizer checks if the target application should orphan child resources
 func hasOrphanFinalizer(app *v1beta1.Application) bool {
 	return slices.Contains(app.GetFinalizers(), oam.FinalizerOrphanResource)
@@ -17,14 +17,19 @@ limitations under the License.
 package resourcekeeper
 
 import (
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
 )
 
@@ -54,3 +59,35 @@ var _ = Describe("Test ResourceKeeper utilities", func() {
 	})
 
 })
+
+func TestGCPolicyAnnotation(t *testing.T) {
+	r := require.New(t)
+	newManifest := func(name, gcPolicy string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		obj.SetName(name)
+		if gcPolicy != "" {
+			obj.SetAnnotations(map[string]string{oam.AnnotationGCPolicy: gcPolicy})
+		}
+		return obj
+	}
+
+	h := &resourceKeeper{}
+	r.Nil(h.findGarbageCollectStrategy(newManifest("a", "")))
+	r.Equal(v1alpha1.GarbageCollectStrategyNever, *h.findGarbageCollectStrategy(newManifest("a", oam.GCPolicyNever)))
+	h.garbageCollectPolicy = &v1alpha1.GarbageCollectPolicySpec{Rules: []v1alpha1.GarbageCollectPolicyRule{{
+		Selector: v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"ConfigMap"}},
+		Strategy: v1alpha1.GarbageCollectStrategyOnAppDelete,
+	}}}
+	r.Equal(v1alpha1.GarbageCollectStrategyOnAppDelete, *h.findGarbageCollectStrategy(newManifest("a", oam.GCPolicyNever)))
+
+	isOrphan, opts := findDeleteOption(newManifest("a", oam.GCPolicyOrphan))
+	r.True(isOrphan)
+	r.Len(opts, 1)
+	isOrphan, opts = findDeleteOption(newManifest("a", oam.GCPolicyCascade))
+	r.False(isOrphan)
+	r.Len(opts, 1)
+	isOrphan, opts = findDeleteOption(newManifest("a", ""))
+	r.False(isOrphan)
+	r.Len(opts, 0)
+}
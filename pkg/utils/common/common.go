@@ -66,6 +66,7 @@ import (
 	oamcore "github.com/oam-dev/kubevela/apis/core.oam.dev"
 	"github.com/oam-dev/kubevela/apis/types"
 	velacue "github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	"github.com/oam-dev/kubevela/pkg/cue/process"
 	"github.com/oam-dev/kubevela/pkg/oam"
 )
@@ -213,7 +214,7 @@ func GetCUEParameterValue(cueStr string) (cue.Value, error) {
 
 // GetCUExParameterValue converts definitions with cuex imports to cue format and extracts parameter field
 func GetCUExParameterValue(ctx context.Context, cueStr string) (cue.Value, error) {
-	template, err := cuex.DefaultCompiler.Get().CompileStringWithOptions(
+	template, err := definition.GetCompiler(cueStr).CompileStringWithOptions(
 		ctx,
 		cueStr+velacue.BaseTemplate,
 		cuex.DisableResolveProviderFunctions{},
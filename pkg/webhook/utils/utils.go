@@ -23,8 +23,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/kubevela/pkg/cue/cuex"
-
 	"cuelang.org/go/cue/cuecontext"
 	cueErrors "cuelang.org/go/cue/errors"
 	"github.com/pkg/errors"
@@ -35,6 +33,7 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1beta1/core"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 )
 
 // ContextRegex to match '**: reference "context" not found'
@@ -77,7 +76,7 @@ func ValidateCueTemplate(cueTemplate string) error {
 
 // ValidateCuexTemplate validate cueTemplate with CueX for types utilising it
 func ValidateCuexTemplate(ctx context.Context, cueTemplate string) error {
-	val, err := cuex.DefaultCompiler.Get().CompileStringWithOptions(ctx, cueTemplate)
+	val, err := definition.GetCompiler(cueTemplate).CompileStringWithOptions(ctx, cueTemplate)
 	if err != nil {
 		return err
 	}
@@ -249,6 +249,22 @@ func TestValidateCuexTemplate(t *testing.T) {
 			`,
 			want: nil,
 		},
+		"withRegisteredPackageImports": {
+			cueTemplate: `
+				import "vela/cel"
+
+				check: cel.#Eval & {
+					$params: expression: "1 + 2"
+				}
+
+				output: {
+					metadata: {
+						name: context.name + "\(check.$returns.result)"
+					}
+				}
+			`,
+			want: nil,
+		},
 		"inValidCueTemp": {
 			cueTemplate: `
 				output: {
@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	wfTypesv1alpha1 "github.com/kubevela/pkg/apis/oam/v1alpha1"
+	cuexruntime "github.com/kubevela/pkg/cue/cuex/runtime"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -40,6 +41,7 @@ import (
 	corev1beta1 "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/appfile/dryrun"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	pkgdef "github.com/oam-dev/kubevela/pkg/definition"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
@@ -57,6 +59,8 @@ type DryRunCmdOptions struct {
 	OfflineMode          bool
 	MergeStandaloneFiles bool
 	DefinitionNamespace  string
+	MockConfigsFile      string
+	MockPackages         []string
 }
 
 // NewDryRunCommand creates `dry-run` command
@@ -90,6 +94,9 @@ vela dry-run -f app.yaml
 
 # dry-run application with policy and workflow
 vela dry-run -f app.yaml -f policy.yaml -f workflow.yaml
+
+# dry-run application with the configs and the vela/kube package read by the definitions mocked
+vela dry-run -f app.yaml --mock-configs configs.yaml --mock-package kube=kube.cue
 `,
 		Annotations: map[string]string{
 			types.TagCommandType:  types.TypeApp,
@@ -126,6 +133,8 @@ vela dry-run -f app.yaml -f policy.yaml -f workflow.yaml
 	cmd.Flags().BoolVar(&o.OfflineMode, "offline", false, "Run `dry-run` in offline / local mode, all validation steps will be skipped")
 	cmd.Flags().BoolVar(&o.MergeStandaloneFiles, "merge", false, "Merge standalone files to produce dry-run results")
 	cmd.Flags().StringVarP(&o.DefinitionNamespace, "definition-namespace", "x", "", "Specify which namespace the definition locates. (default \"vela-system\")")
+	cmd.Flags().StringVar(&o.MockConfigsFile, "mock-configs", "", "Specify a yaml file mapping <namespace>/<name> to the properties of the configs read by the definitions, instead of reading them from the cluster")
+	cmd.Flags().StringSliceVar(&o.MockPackages, "mock-package", nil, "Specify <name>=<file.cue> to render the definitions with the CUE file in place of the vela/<name> package")
 	addNamespaceAndEnvArg(cmd)
 	cmd.SetOut(ioStreams.Out)
 	return cmd
@@ -164,8 +173,15 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 	}
 
 	dryRunOpt := dryrun.NewDryRunOption(newClient, config, objs, false)
+	if cmdOption.OfflineMode {
+		dryRunOpt.WithOfflineRender()
+	}
 	ctx := oamutil.SetNamespaceInCtx(context.Background(), namespace)
 	ctx = oamutil.SetXDefinitionNamespaceInCtx(ctx, cmdOption.DefinitionNamespace)
+	ctx, err = withDryRunMocks(ctx, cmdOption)
+	if err != nil {
+		return buff, err
+	}
 
 	// Perform validation only if not in offline mode
 	if !cmdOption.OfflineMode {
@@ -205,6 +221,41 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 	return buff, nil
 }
 
+// withDryRunMocks returns the context rendering the definitions with the mock configs and packages of the options
+func withDryRunMocks(ctx context.Context, cmdOption *DryRunCmdOptions) (context.Context, error) {
+	if cmdOption.MockConfigsFile != "" {
+		data, err := os.ReadFile(filepath.Clean(cmdOption.MockConfigsFile))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read mock configs %s", cmdOption.MockConfigsFile)
+		}
+		configs := definition.MockConfigs{}
+		if err := yaml.Unmarshal(data, &configs); err != nil {
+			return nil, errors.Wrapf(err, "parse mock configs %s", cmdOption.MockConfigsFile)
+		}
+		ctx = definition.WithMockConfigs(ctx, configs)
+	}
+	var pkgs []cuexruntime.Package
+	for _, mock := range cmdOption.MockPackages {
+		name, path, found := strings.Cut(mock, "=")
+		if !found || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid mock package %q, it should be <name>=<file.cue>", mock)
+		}
+		src, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read mock package %s", name)
+		}
+		pkg, err := cuexruntime.NewInternalPackage(name, string(src), nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load mock package %s", name)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	if len(pkgs) > 0 {
+		ctx = definition.WithMockProviders(ctx, pkgs...)
+	}
+	return ctx, nil
+}
+
 func readObj(path string) (*unstructured.Unstructured, error) {
 	switch {
 	case strings.HasSuffix(path, CUEExtension):
@@ -259,6 +259,18 @@ var _ = Describe("Testing dry-run", func() {
 		Expect(buff.String()).Should(ContainSubstring("workload.oam.dev/type: myworker"))
 	})
 
+	It("Testing dry-run offline with mock package", func() {
+		c := common2.Args{}
+		opt := DryRunCmdOptions{ApplicationFiles: []string{"test-data/dry-run/mock/testing-dry-run-mock.yaml"}, DefinitionFile: "test-data/dry-run/mock/fixture-worker-def.yaml", OfflineMode: true, MockPackages: []string{"fixture=test-data/dry-run/mock/fixture.cue"}}
+		buff, err := DryRunApplication(&opt, c, "", "")
+		Expect(err).Should(BeNil())
+		Expect(buff.String()).Should(ContainSubstring("image: oamdev/mocked:v1"))
+
+		opt.MockPackages = []string{"test-data/dry-run/mock/fixture.cue"}
+		_, err = DryRunApplication(&opt, c, "", "")
+		Expect(err).ShouldNot(BeNil())
+	})
+
 	It("Testing dry-run with default application namespace", func() {
 		c := common2.Args{}
 		c.SetConfig(cfg)
@@ -0,0 +1,3 @@
+package fixture
+
+image: "oamdev/mocked:v1"
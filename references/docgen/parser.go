@@ -46,6 +46,7 @@ import (
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/controller/utils"
 	velacue "github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/pkg/cue/definition"
 	pkgdef "github.com/oam-dev/kubevela/pkg/definition"
 	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
@@ -700,7 +701,7 @@ func WalkParameterSchema(parameters *openapi3.Schema, name string, depth int) er
 
 // GetBaseResourceKinds helps get resource.group string of components' base resource
 func GetBaseResourceKinds(ctx context.Context, cueStr string, mapper meta.RESTMapper) (string, error) {
-	tmpl, err := cuex.DefaultCompiler.Get().CompileStringWithOptions(
+	tmpl, err := definition.GetCompiler(cueStr).CompileStringWithOptions(
 		ctx,
 		cueStr+velacue.BaseTemplate,
 		cuex.DisableResolveProviderFunctions{},